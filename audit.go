@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// Audit log defaults
+const (
+	defaultAuditMaxBytes = 10 << 20
+	defaultAuditMaxFiles = 5
+	// auditMaxArgLength truncates long argument values such as issue bodies
+	auditMaxArgLength = 200
+)
+
+// auditEntry is one line of the audit log
+type auditEntry struct {
+	Time        time.Time              `json:"time"`
+	Session     string                 `json:"session,omitempty" jsonschema:"description=MCP session the call was made in"`
+	Tool        string                 `json:"tool"`
+	Arguments   map[string]interface{} `json:"arguments,omitempty" jsonschema:"description=Call arguments with secrets redacted and long values truncated"`
+	Identity    string                 `json:"identity" jsonschema:"description=GitHub login the call acted as"`
+	Mutating    bool                   `json:"mutating" jsonschema:"description=True for tools that can modify GitHub"`
+	Status      string                 `json:"status" jsonschema:"enum=ok,enum=error"`
+	Error       string                 `json:"error,omitempty"`
+	ErrorCode   string                 `json:"error_code,omitempty" jsonschema:"description=Stable code classifying the error"`
+	ResourceURL string                 `json:"resource_url,omitempty" jsonschema:"description=URL of the resource the call created"`
+	DurationMS  int64                  `json:"duration_ms"`
+}
+
+// auditLog appends an entry for every tool call to a JSONL file. Once the
+// file grows past maxBytes it is rotated to path.1, keeping maxFiles old files.
+type auditLog struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+	now      func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	// rotations counts rotations, so readers can tell the files moved under them
+	rotations int
+}
+
+// openAuditLog opens path for appending. Non-positive limits use the defaults.
+func openAuditLog(path string, maxBytes int64, maxFiles int) (*auditLog, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultAuditMaxBytes
+	}
+	if maxFiles <= 0 {
+		maxFiles = defaultAuditMaxFiles
+	}
+	l := &auditLog{path: path, maxBytes: maxBytes, maxFiles: maxFiles, now: time.Now}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *auditLog) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Close closes the underlying file
+func (l *auditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// write appends entry as one JSON line, rotating first when it would not fit
+func (l *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 && l.size+int64(len(line)) > l.maxBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	return err
+}
+
+// rotate shifts path.N-1 … path.1 up by one, dropping the oldest, and starts a fresh path
+func (l *auditLog) rotate() error {
+	l.rotations++
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(l.rotatedPath(i), l.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.rotatedPath(1)); err != nil {
+		return fmt.Errorf("rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+// rotatedPath is the name of the i-th oldest rotated file, or the live file for 0
+func (l *auditLog) rotatedPath(i int) string {
+	if i == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// recent returns up to limit of the newest entries matching keep, newest first,
+// reading back through the rotated files as needed. The files are read without
+// holding the lock, so tool calls are not held up by a long read.
+func (l *auditLog) recent(limit int, keep func(auditEntry) bool) ([]auditEntry, error) {
+	for {
+		l.mu.Lock()
+		rotations := l.rotations
+		l.mu.Unlock()
+
+		out, err := l.read(limit, keep)
+
+		// A rotation while reading shifts the files along, which can skip or
+		// repeat entries: read them again
+		l.mu.Lock()
+		rotated := l.rotations != rotations
+		l.mu.Unlock()
+		if !rotated {
+			return out, err
+		}
+	}
+}
+
+// read collects the entries for recent from the live and rotated files
+func (l *auditLog) read(limit int, keep func(auditEntry) bool) ([]auditEntry, error) {
+	var out []auditEntry
+	for i := 0; i <= l.maxFiles && len(out) < limit; i++ {
+		entries, err := readAuditFile(l.rotatedPath(i))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for j := len(entries) - 1; j >= 0 && len(out) < limit; j-- {
+			if keep == nil || keep(entries[j]) {
+				out = append(out, entries[j])
+			}
+		}
+	}
+	return out, nil
+}
+
+// readAuditFile parses a JSONL audit file, skipping lines that do not parse
+func readAuditFile(path string) ([]auditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var entry auditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// middleware records every call to the tools in mutating, and to all others as read-only calls
+func (l *auditLog) middleware(svc *tools.Service, mutating map[string]bool) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := l.now()
+			result, err := next(ctx, req)
+
+			args := req.GetArguments()
+			owner, _ := args["owner"].(string)
+			entry := auditEntry{
+				Time:       start.UTC(),
+				Tool:       req.Params.Name,
+				Arguments:  sanitizeArguments(args),
+				Identity:   svc.Identity(ctx, owner),
+				Mutating:   mutating[req.Params.Name],
+				Status:     "ok",
+				DurationMS: l.now().Sub(start).Milliseconds(),
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				entry.Session = session.SessionID()
+			}
+			switch {
+			case err != nil:
+				entry.Status, entry.Error = "error", err.Error()
+			case result != nil && result.IsError:
+				entry.Status, entry.Error, entry.ErrorCode = "error", resultMessage(result), errorCode(result)
+			case result != nil:
+				if created, ok := result.StructuredContent.(createIssueResult); ok && created.Issue != nil {
+					entry.ResourceURL = created.Issue.URL
+				}
+			}
+
+			if werr := l.write(entry); werr != nil {
+				fmt.Fprintf(os.Stderr, "Audit log error: %v\n", werr)
+			}
+			return result, err
+		}
+	}
+}
+
+// auditLogInput is the input of get_audit_log
+type auditLogInput struct {
+	Limit        int    `json:"limit,omitempty" jsonschema:"minimum=1,maximum=200" jsonschema_description:"Maximum number of entries to return, at most 200. Defaults to 20"`
+	Tool         string `json:"tool,omitempty" jsonschema_description:"Only return calls to this tool"`
+	MutatingOnly bool   `json:"mutating_only,omitempty" jsonschema_description:"Only return calls to tools that can modify GitHub"`
+}
+
+// auditLogHandler serves get_audit_log from l
+func auditLogHandler(l *auditLog) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		raw, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
+		}
+		input, err := tools.DecodeInput[auditLogInput](raw)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		if input.Limit == 0 {
+			input.Limit = 20
+		}
+
+		entries, err := l.recent(input.Limit, func(entry auditEntry) bool {
+			return (input.Tool == "" || entry.Tool == input.Tool) && (!input.MutatingOnly || entry.Mutating)
+		})
+		if err != nil {
+			return errorResult(err), nil
+		}
+
+		var output strings.Builder
+		fmt.Fprintf(&output, "📜 %d audit log entries (newest first):\n\n", len(entries))
+		for _, entry := range entries {
+			marker := ""
+			if entry.Mutating {
+				marker = " ✏️"
+			}
+			fmt.Fprintf(&output, "- %s %s%s by %s: %s (%dms)",
+				entry.Time.Format(time.RFC3339), entry.Tool, marker, entry.Identity, entry.Status, entry.DurationMS)
+			if entry.ResourceURL != "" {
+				fmt.Fprintf(&output, " → %s", entry.ResourceURL)
+			}
+			output.WriteString("\n")
+		}
+
+		if entries == nil {
+			entries = []auditEntry{}
+		}
+		return mcp.NewToolResultStructured(auditLogResult{Entries: entries}, output.String()), nil
+	}
+}
+
+// resultMessage returns the text of a tool result
+func resultMessage(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// sanitizeArguments redacts secret-looking arguments and truncates long values
+func sanitizeArguments(args map[string]interface{}) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		lower := strings.ToLower(key)
+		switch {
+		case strings.Contains(lower, "token"), strings.Contains(lower, "secret"),
+			strings.Contains(lower, "password"), strings.Contains(lower, "key"):
+			out[key] = "[REDACTED]"
+		default:
+			if s, ok := value.(string); ok {
+				if runes := []rune(s); len(runes) > auditMaxArgLength {
+					value = string(runes[:auditMaxArgLength]) + "…"
+				}
+			}
+			out[key] = value
+		}
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAuditLog opens an audit log in a temporary directory
+func newTestAuditLog(t *testing.T, maxBytes int64, maxFiles int) *auditLog {
+	t.Helper()
+	l, err := openAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"), maxBytes, maxFiles)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestAuditLogRecordsToolCalls(t *testing.T) {
+	audit := newTestAuditLog(t, 0, 0)
+	s, _ := newTestServerWith(t, serverOptions{Audit: audit})
+
+	_, err := callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	_, err = callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "title": "Flaky test", "body": strings.Repeat("x", 500),
+	})
+	require.NoError(t, err)
+	_, err = callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "missing"})
+	require.NoError(t, err)
+
+	entries, err := readAuditFile(audit.path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "list_issues", entries[0].Tool)
+	assert.False(t, entries[0].Mutating)
+	assert.Equal(t, "ok", entries[0].Status)
+	assert.Equal(t, "anonymous", entries[0].Identity)
+	assert.Equal(t, "widgets", entries[0].Arguments["repo"])
+
+	assert.Equal(t, "create_issue", entries[1].Tool)
+	assert.True(t, entries[1].Mutating)
+	assert.Equal(t, "https://github.com/acme/widgets/issues/5", entries[1].ResourceURL)
+	assert.Len(t, []rune(entries[1].Arguments["body"].(string)), auditMaxArgLength+1)
+
+	assert.Equal(t, "error", entries[2].Status)
+	assert.Equal(t, errCodeNotFound, entries[2].ErrorCode)
+	assert.NotEmpty(t, entries[2].Error)
+}
+
+func TestAuditLogRotates(t *testing.T) {
+	audit := newTestAuditLog(t, 200, 2)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, audit.write(auditEntry{Time: time.Unix(int64(i), 0).UTC(), Tool: "list_issues", Status: "ok"}))
+	}
+
+	_, err := os.Stat(audit.path + ".2")
+	assert.NoError(t, err)
+	_, err = os.Stat(audit.path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	entries, err := audit.recent(100, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Less(t, len(entries), 10)
+	assert.Equal(t, int64(9), entries[0].Time.Unix())
+	for i := 1; i < len(entries); i++ {
+		assert.True(t, entries[i].Time.Before(entries[i-1].Time), "entries are newest first")
+	}
+}
+
+func TestAuditLogRecentWhileWriting(t *testing.T) {
+	audit := newTestAuditLog(t, 400, 3)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			assert.NoError(t, audit.write(auditEntry{Time: time.Unix(int64(i), 0).UTC(), Tool: "list_issues", Status: "ok"}))
+		}
+	}()
+
+	// Reads racing writes and rotations never repeat or reorder entries
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		entries, err := audit.recent(100, nil)
+		require.NoError(t, err)
+		for i := 1; i < len(entries); i++ {
+			require.True(t, entries[i].Time.Before(entries[i-1].Time), "entries are newest first")
+		}
+	}
+}
+
+func TestAuditLogTool(t *testing.T) {
+	audit := newTestAuditLog(t, 0, 0)
+	s, _ := newTestServerWith(t, serverOptions{Audit: audit})
+
+	_, err := callTool(t, s, "list_prs", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	_, err = callTool(t, s, "create_issue", map[string]interface{}{"owner": "acme", "repo": "widgets", "title": "Flaky test"})
+	require.NoError(t, err)
+
+	result, err := callTool(t, s, "get_audit_log", map[string]interface{}{"mutating_only": true})
+	require.NoError(t, err)
+	entries := result.StructuredContent.(auditLogResult).Entries
+	require.Len(t, entries, 1)
+	assert.Equal(t, "create_issue", entries[0].Tool)
+	assert.Contains(t, resultText(t, result), "create_issue ✏️ by anonymous: ok")
+
+	result, err = callTool(t, s, "get_audit_log", map[string]interface{}{"limit": 10})
+	require.NoError(t, err)
+	assert.Len(t, result.StructuredContent.(auditLogResult).Entries, 3)
+}
+
+func TestAuditToolRequiresAuditLog(t *testing.T) {
+	s, _ := newTestServer(t)
+	assert.NotContains(t, toolNames(s), "get_audit_log")
+}
+
+func TestAuditToolNotOfferedToSharedServers(t *testing.T) {
+	s, _ := newTestServerWith(t, serverOptions{Audit: newTestAuditLog(t, 0, 0), MultiTenant: true})
+	assert.NotContains(t, toolNames(s), "get_audit_log")
+	assert.Contains(t, toolNames(s), "list_prs")
+
+	for _, transport := range []string{transportSSE, transportHTTP} {
+		cfg := defaultConfig()
+		cfg.Transport.Transport = transport
+		assert.True(t, cfg.serverOptions().MultiTenant)
+
+		cfg.Toolsets = []string{toolsetIssues, toolsetAudit}
+		assert.ErrorContains(t, cfg.validate(), "audit toolset is only available over stdio")
+	}
+	assert.NoError(t, defaultConfig().validate())
+}
+
+func TestSanitizeArguments(t *testing.T) {
+	args := sanitizeArguments(map[string]interface{}{
+		"owner":        "acme",
+		"github_token": "ghp_secret",
+		"api_key":      "k",
+		"limit":        float64(5),
+	})
+	assert.Equal(t, "acme", args["owner"])
+	assert.Equal(t, "[REDACTED]", args["github_token"])
+	assert.Equal(t, "[REDACTED]", args["api_key"])
+	assert.Equal(t, float64(5), args["limit"])
+	assert.Nil(t, sanitizeArguments(nil))
+}
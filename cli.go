@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+	"github.com/himanshusharma89/github-mcp-server/tools/recorder"
+)
+
+const usage = `Usage:
+  github-mcp-server [serve] [flags]                 Run the MCP server (the default)
+  github-mcp-server list-tools [--json] [flags]     Print the registered tools and their input schemas
+  github-mcp-server call <tool> [--arg k=v]... [--json] [flags]
+                                                    Call a tool once and print its result
+
+Every command accepts the server flags; run "github-mcp-server serve -h" to list them.
+`
+
+// run executes the command line args and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = serveCommand(args, stderr)
+	case "list-tools":
+		err = listToolsCommand(args, stdout, stderr)
+	case "call":
+		err = callCommand(args, stdout, stderr)
+	case "help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", command, usage)
+		return 2
+	}
+
+	var exit exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &exit):
+		return int(exit)
+	default:
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+}
+
+// exitError ends a command with a non-zero exit code after its output is written
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// newFlagSet returns a flag set for command with the configuration flags registered
+func newFlagSet(command string, stderr io.Writer) (*flag.FlagSet, func() (serverConfig, error)) {
+	fs := flag.NewFlagSet("github-mcp-server "+command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs, configFlags(fs)
+}
+
+// newServer builds the GitHub service and the MCP server described by cfg.
+// The returned function closes the audit log, if any.
+func newServer(cfg serverConfig) (*server.MCPServer, *tools.Service, serverOptions, func(), error) {
+	opts := cfg.serverOptions()
+	cleanup := func() {}
+	fail := func(err error) (*server.MCPServer, *tools.Service, serverOptions, func(), error) {
+		cleanup()
+		return nil, nil, serverOptions{}, nil, err
+	}
+
+	if cfg.Audit.Path != "" {
+		audit, err := openAuditLog(cfg.Audit.Path, int64(cfg.Audit.MaxMB)<<20, cfg.Audit.MaxFiles)
+		if err != nil {
+			return fail(err)
+		}
+		opts.Audit = audit
+		cleanup = func() { audit.Close() }
+	}
+
+	githubCfg, err := cfg.githubConfig()
+	if err != nil {
+		return fail(fmt.Errorf("GitHub configuration: %w", err))
+	}
+	if cfg.Record != "" {
+		rec, err := recorder.NewRecorder(cfg.Record, recorder.ModeRecord, nil)
+		if err != nil {
+			return fail(fmt.Errorf("recorder: %w", err))
+		}
+		githubCfg.Transport = rec
+	}
+	svc, err := tools.NewService(githubCfg)
+	if err != nil {
+		return fail(fmt.Errorf("GitHub client: %w", err))
+	}
+
+	return newMCPServer(svc, opts), svc, opts, cleanup, nil
+}
+
+// serveCommand runs the MCP server on the configured transport
+func serveCommand(args []string, stderr io.Writer) error {
+	fs, resolve := newFlagSet("serve", stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := resolve()
+	if err != nil {
+		return fmt.Errorf("configuration: %w", err)
+	}
+
+	s, _, _, cleanup, err := newServer(cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	return serve(s, cfg.Transport)
+}
+
+// listToolsCommand prints every registered tool with its input schema
+func listToolsCommand(args []string, stdout, stderr io.Writer) error {
+	fs, resolve := newFlagSet("list-tools", stderr)
+	asJSON := fs.Bool("json", false, "Print the tool definitions as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := resolve()
+	if err != nil {
+		return fmt.Errorf("configuration: %w", err)
+	}
+
+	s, _, _, cleanup, err := newServer(cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var list []mcp.Tool
+	for _, t := range s.ListTools() {
+		list = append(list, t.Tool)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+
+	for _, tool := range list {
+		mode := "read-only"
+		if isMutating(tool) {
+			mode = "mutating"
+		}
+		fmt.Fprintf(stdout, "%s (%s)\n  %s\n", tool.Name, mode, tool.Description)
+
+		names := make([]string, 0, len(tool.InputSchema.Properties))
+		for name := range tool.InputSchema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, _ := tool.InputSchema.Properties[name].(map[string]any)
+			required := ""
+			if contains(tool.InputSchema.Required, name) {
+				required = ", required"
+			}
+			fmt.Fprintf(stdout, "    --arg %s=<%v%s>  %v\n", name, prop["type"], required, prop["description"])
+		}
+		fmt.Fprintln(stdout)
+	}
+	return nil
+}
+
+// callCommand invokes one tool handler directly and prints its result
+func callCommand(args []string, stdout, stderr io.Writer) error {
+	fs, resolve := newFlagSet("call", stderr)
+	var toolArgs argList
+	fs.Var(&toolArgs, "arg", "Tool argument as key=value; repeat for each argument")
+	asJSON := fs.Bool("json", false, "Print the whole result, including structured content, as JSON")
+
+	// The tool name may come before or after the flags
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if name == "" {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		fs.Usage()
+		return exitError(2)
+	}
+	cfg, err := resolve()
+	if err != nil {
+		return fmt.Errorf("configuration: %w", err)
+	}
+
+	s, svc, opts, cleanup, err := newServer(cfg)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	registered := s.GetTool(name)
+	if registered == nil {
+		return fmt.Errorf("unknown tool %q; run list-tools to see the available tools", name)
+	}
+	handler := registered.Handler
+	if opts.Audit != nil {
+		handler = opts.Audit.middleware(svc, map[string]bool{name: isMutating(registered.Tool)})(handler)
+	}
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = toolArgs.arguments()
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(stdout, resultMessage(result))
+	}
+	if result.IsError {
+		return exitError(1)
+	}
+	return nil
+}
+
+// argList collects repeated --arg key=value flags
+type argList []string
+
+func (a *argList) String() string {
+	return strings.Join(*a, " ")
+}
+
+func (a *argList) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	*a = append(*a, value)
+	return nil
+}
+
+// arguments returns the collected values by key. They are passed as strings
+// and converted to the tool's argument types like any other call.
+func (a argList) arguments() map[string]any {
+	out := make(map[string]any, len(a))
+	for _, pair := range a {
+		key, value, _ := strings.Cut(pair, "=")
+		out[key] = value
+	}
+	return out
+}
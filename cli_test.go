@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools/githubtest"
+)
+
+// cliConfig writes a config file pointing the server at a fake GitHub seeded with acme/widgets
+func cliConfig(t *testing.T) (string, *githubtest.Server) {
+	t.Helper()
+	clearConfigEnv(t)
+	fake := githubtest.NewServer(&githubtest.Repo{
+		Owner: "acme",
+		Name:  "widgets",
+		Issues: []*github.Issue{
+			{Number: github.Int(1), Title: github.String("Crash on startup"), State: github.String("open")},
+		},
+	})
+	t.Cleanup(fake.Close)
+	return writeConfig(t, "config.yaml", fmt.Sprintf("github:\n  base_url: %s\n", fake.URL)), fake
+}
+
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLIListTools(t *testing.T) {
+	config, _ := cliConfig(t)
+
+	code, out, _ := runCLI("list-tools", "--config", config, "--read-only")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "list_issues (read-only)")
+	assert.Contains(t, out, "--arg owner=<string, required>")
+	assert.NotContains(t, out, "create_issue")
+
+	code, out, _ = runCLI("list-tools", "--json", "--config", config)
+	require.Equal(t, 0, code)
+	var list []mcp.Tool
+	require.NoError(t, json.Unmarshal([]byte(out), &list))
+	var names []string
+	for _, tool := range list {
+		names = append(names, tool.Name)
+	}
+	assert.Contains(t, names, "create_issue")
+	assert.IsIncreasing(t, names)
+}
+
+func TestCLICall(t *testing.T) {
+	config, fake := cliConfig(t)
+
+	code, out, _ := runCLI("call", "list_issues", "--config", config, "--arg", "owner=acme", "--arg", "repo=widgets")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Crash on startup")
+
+	// The tool name may also follow the flags, and numbers and booleans are converted
+	code, out, _ = runCLI("call", "--config", config, "--json",
+		"--arg", "owner=acme", "--arg", "repo=widgets", "--arg", "title=New", "--arg", "dry_run=true", "create_issue")
+	require.Equal(t, 0, code)
+	var result struct {
+		StructuredContent createIssueResult `json:"structuredContent"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.True(t, result.StructuredContent.DryRun)
+	assert.Equal(t, "New", result.StructuredContent.Request.GetTitle())
+	assert.Len(t, fake.Repo("acme", "widgets").Issues, 1, "dry run must not create the issue")
+}
+
+func TestCLICallErrors(t *testing.T) {
+	config, _ := cliConfig(t)
+
+	code, out, _ := runCLI("call", "list_issues", "--config", config, "--arg", "owner=acme", "--arg", "repo=missing")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, "Error code: not_found")
+
+	code, _, errOut := runCLI("call", "no_such_tool", "--config", config)
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, `unknown tool "no_such_tool"`)
+
+	code, out, _ = runCLI("call", "analyze_issue_priority", "--config", config, "--arg", "owner=acme",
+		"--arg", "repo=widgets", "--arg", "limit=many")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, out, `limit must be an integer, got "many"`)
+
+	code, _, errOut = runCLI("frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, `unknown command "frobnicate"`)
+}
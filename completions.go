@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// maxCompletionValues is the most values a completion may return
+const maxCompletionValues = 100
+
+// completionProvider completes the owner, repo, labels and assignee arguments
+// of prompts and resource templates. MCP only defines completion for those,
+// so clients complete tool arguments by naming the tool in a prompt reference;
+// arguments are matched by name whatever the reference.
+type completionProvider struct {
+	svc *tools.Service
+}
+
+func (p completionProvider) CompletePromptArgument(ctx context.Context, _ string, arg mcp.CompleteArgument, c mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, arg, c.Arguments), nil
+}
+
+func (p completionProvider) CompleteResourceArgument(ctx context.Context, _ string, arg mcp.CompleteArgument, c mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(ctx, arg, c.Arguments), nil
+}
+
+// complete suggests values for arg given the arguments already filled in.
+// Completion is best effort: a lookup that fails, for example because the
+// repo is still being typed, suggests nothing rather than erroring.
+func (p completionProvider) complete(ctx context.Context, arg mcp.CompleteArgument, args map[string]string) *mcp.Completion {
+	owner, repo := strings.TrimSpace(args["owner"]), strings.TrimSpace(args["repo"])
+
+	// A list argument written as "bug, do" completes its last item
+	head, value := "", arg.Value
+	if i := strings.LastIndex(value, ","); i >= 0 && arg.Name == "labels" {
+		head, value = value[:i+1]+" ", strings.TrimSpace(value[i+1:])
+	}
+
+	var values []string
+	var err error
+	switch arg.Name {
+	case "owner":
+		values, err = p.svc.CompleteOwners(ctx, value)
+	case "repo":
+		values, err = p.svc.CompleteRepos(ctx, owner, value)
+	case "labels", "label":
+		values, err = p.svc.CompleteLabels(ctx, owner, repo, value)
+	case "assignee", "assignees":
+		values, err = p.svc.CompleteAssignees(ctx, owner, repo, value)
+	}
+	if err != nil || len(values) == 0 {
+		return &mcp.Completion{Values: []string{}}
+	}
+
+	total := len(values)
+	if total > maxCompletionValues {
+		values = values[:maxCompletionValues]
+	}
+	for i, v := range values {
+		values[i] = head + v
+	}
+	return &mcp.Completion{Values: values, Total: total, HasMore: total > len(values)}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// complete sends a completion/complete request through the MCP server
+func complete(t *testing.T, s *server.MCPServer, ref map[string]string, name, value string, args map[string]string) mcp.Completion {
+	t.Helper()
+	msg, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "completion/complete",
+		"params": map[string]interface{}{
+			"ref":      ref,
+			"argument": map[string]string{"name": name, "value": value},
+			"context":  map[string]interface{}{"arguments": args},
+		},
+	})
+	require.NoError(t, err)
+
+	resp, ok := s.HandleMessage(context.Background(), msg).(mcp.JSONRPCResponse)
+	require.True(t, ok, "completion/complete failed")
+	result, ok := resp.Result.(mcp.CompleteResult)
+	require.True(t, ok, "unexpected result %T", resp.Result)
+	return result.Completion
+}
+
+func TestCompletePromptArguments(t *testing.T) {
+	s, _ := newTestServer(t)
+	prompt := map[string]string{"type": "ref/prompt", "name": "triage_repo"}
+
+	c := complete(t, s, prompt, "repo", "wid", map[string]string{"owner": "acme"})
+	assert.Equal(t, []string{"widgets"}, c.Values)
+	assert.Equal(t, 1, c.Total)
+
+	// Lookups that fail, here without a token to list orgs with, complete nothing
+	c = complete(t, s, prompt, "owner", "ac", nil)
+	assert.Empty(t, c.Values)
+}
+
+func TestCompleteToolArguments(t *testing.T) {
+	s, _ := newTestServer(t)
+	tool := map[string]string{"type": "ref/prompt", "name": "create_issue"}
+	repo := map[string]string{"owner": "acme", "repo": "widgets"}
+
+	c := complete(t, s, tool, "labels", "docs, H", repo)
+	assert.Equal(t, []string{"docs, help wanted"}, c.Values)
+
+	c = complete(t, s, tool, "assignee", "b", repo)
+	assert.Equal(t, []string{"bob"}, c.Values)
+
+	// Labels need the repository they are defined in
+	c = complete(t, s, tool, "labels", "d", map[string]string{"owner": "acme"})
+	assert.Empty(t, c.Values)
+}
+
+func TestCompleteResourceArguments(t *testing.T) {
+	s, _ := newTestServer(t)
+	ref := map[string]string{"type": "ref/resource", "uri": "github://{owner}/{repo}/issues/{number}"}
+
+	c := complete(t, s, ref, "repo", "", map[string]string{"owner": "acme"})
+	assert.Equal(t, []string{"widgets"}, c.Values)
+}
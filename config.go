@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// Supported GitHub authentication methods
+const (
+	authAuto  = ""
+	authToken = "token"
+	authApp   = "app"
+	authNone  = "none"
+)
+
+// serverConfig is every server setting. It is built from, in increasing
+// precedence, the defaults, the --config file, environment variables and flags.
+type serverConfig struct {
+	Server     serverInfo           `yaml:"server"`
+	Transport  transportConfig      `yaml:"transport"`
+	GitHub     githubConfig         `yaml:"github"`
+	Toolsets   []string             `yaml:"toolsets"`
+	ReadOnly   bool                 `yaml:"read_only"`
+	DryRun     bool                 `yaml:"dry_run"`
+	Pagination paginationConfig     `yaml:"pagination"`
+	Retry      retryConfig          `yaml:"retry"`
+	Cache      cacheConfig          `yaml:"cache"`
+	Priority   tools.PriorityConfig `yaml:"priority"`
+	Policy     tools.Policy         `yaml:"policy"`
+	Audit      auditConfig          `yaml:"audit"`
+	// Record is the --record cassette path; it is not read from the file
+	Record string `yaml:"-"`
+}
+
+// serverInfo is what the server reports about itself to clients
+type serverInfo struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+// githubConfig selects the GitHub hosts and how the server authenticates to them
+type githubConfig struct {
+	// BaseURL overrides the github.com API root
+	BaseURL string `yaml:"base_url"`
+	Token   string `yaml:"token"`
+	// Auth is token, app or none. Empty uses the app when one is configured and the token otherwise.
+	Auth       string                 `yaml:"auth"`
+	Enterprise tools.EnterpriseConfig `yaml:"enterprise"`
+	App        appConfig              `yaml:"app"`
+}
+
+// appConfig identifies a GitHub App and its private key
+type appConfig struct {
+	ID             int64  `yaml:"id"`
+	PrivateKey     string `yaml:"private_key"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	InstallationID int64  `yaml:"installation_id"`
+}
+
+// paginationConfig sets listing defaults
+type paginationConfig struct {
+	PerPage int `yaml:"per_page"`
+}
+
+// retryConfig bounds retries of rate limited and failed GitHub reads
+type retryConfig struct {
+	// MaxRetries is how often a read is retried; 0 disables retries
+	MaxRetries int `yaml:"max_retries"`
+	// MaxWait is the longest a retry waits for a rate limit to reset
+	MaxWait time.Duration `yaml:"max_wait"`
+}
+
+// cacheConfig sizes the GitHub response cache
+type cacheConfig struct {
+	// MaxMB caps the cached response bodies in MiB; 0 disables the cache
+	MaxMB int           `yaml:"max_mb"`
+	TTL   time.Duration `yaml:"ttl"`
+}
+
+// auditConfig enables and sizes the audit log
+type auditConfig struct {
+	Path     string `yaml:"path"`
+	MaxMB    int    `yaml:"max_mb"`
+	MaxFiles int    `yaml:"max_files"`
+}
+
+// defaultConfig returns the settings used when nothing overrides them
+func defaultConfig() serverConfig {
+	return serverConfig{
+		Server:     serverInfo{Name: "GitHub MCP Server", Version: "0.1.0"},
+		Transport:  transportConfig{Transport: transportStdio, Addr: ":8080", BasePath: "/mcp"},
+		Pagination: paginationConfig{PerPage: 100},
+		Retry:      retryConfig{MaxRetries: 3, MaxWait: time.Minute},
+		Cache:      cacheConfig{MaxMB: 32, TTL: 10 * time.Minute},
+		Audit:      auditConfig{MaxMB: 10, MaxFiles: 5},
+	}
+}
+
+// parseConfig builds the configuration for the command-line arguments args
+func parseConfig(name string, args []string) (serverConfig, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	resolve := configFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return serverConfig{}, err
+	}
+	return resolve()
+}
+
+// configFlags registers the configuration flags on fs. Once fs is parsed,
+// the returned function builds and validates the configuration.
+func configFlags(fs *flag.FlagSet) func() (serverConfig, error) {
+	def := defaultConfig()
+	configPath := fs.String("config", os.Getenv("MCP_CONFIG"), "YAML or JSON configuration file")
+
+	// Flags are parsed into f and copied over the file and environment only when set
+	f := def
+	fs.StringVar(&f.Transport.Transport, "transport", def.Transport.Transport,
+		"Transport to serve on: stdio, sse or http (streamable HTTP)")
+	fs.StringVar(&f.Transport.Addr, "addr", def.Transport.Addr,
+		"Listen address for the sse and http transports")
+	fs.StringVar(&f.Transport.BasePath, "base-path", def.Transport.BasePath,
+		"Base path the sse and http transports are mounted on")
+	fs.BoolVar(&f.Transport.ShareServerToken, "share-server-token", false,
+		"Let HTTP callers without an Authorization header use GITHUB_TOKEN")
+	fs.BoolVar(&f.ReadOnly, "read-only", false,
+		"Only register tools that do not modify GitHub, and refuse mutating calls")
+	fs.BoolVar(&f.DryRun, "dry-run", false,
+		"Preview create_issue requests instead of sending them, unless a call passes dry_run=false")
+	toolsets := fs.String("toolsets", "all",
+		"Comma-separated toolsets to enable: "+strings.Join(allToolsets, ", ")+" or all")
+	fs.StringVar(&f.GitHub.Auth, "auth", "",
+		"GitHub authentication: token, app or none. Defaults to the app when configured, else the token")
+	fs.IntVar(&f.Pagination.PerPage, "per-page", def.Pagination.PerPage,
+		"Page size listings use when a call sets none, at most 100")
+	fs.IntVar(&f.Retry.MaxRetries, "max-retries", def.Retry.MaxRetries,
+		"How often rate limited or failed GitHub reads are retried; 0 disables retries")
+	fs.DurationVar(&f.Retry.MaxWait, "retry-max-wait", def.Retry.MaxWait,
+		"Longest wait for a rate limit to reset before a call gives up")
+	fs.IntVar(&f.Cache.MaxMB, "cache-max-mb", def.Cache.MaxMB,
+		"Size of the GitHub response cache in MiB; 0 disables it")
+	fs.DurationVar(&f.Cache.TTL, "cache-ttl", def.Cache.TTL,
+		"How long cached GitHub responses are kept for revalidation")
+	fs.StringVar(&f.Audit.Path, "audit-log", "",
+		"Append a JSONL record of every tool call to this file")
+	fs.IntVar(&f.Audit.MaxMB, "audit-log-max-mb", def.Audit.MaxMB, "Rotate the audit log once it reaches this size in MiB")
+	fs.IntVar(&f.Audit.MaxFiles, "audit-log-max-files", def.Audit.MaxFiles, "Number of rotated audit log files to keep")
+	fs.StringVar(&f.Record, "record", "",
+		"Record every GitHub HTTP exchange into this cassette file, with credentials scrubbed")
+
+	return func() (serverConfig, error) {
+		return resolveConfig(fs, f, *configPath, *toolsets)
+	}
+}
+
+// resolveConfig layers the config file at path, the environment and the
+// flags set on fs (parsed into f and toolsets) over the defaults
+func resolveConfig(fs *flag.FlagSet, f serverConfig, path, toolsets string) (serverConfig, error) {
+	cfg := defaultConfig()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return serverConfig{}, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return serverConfig{}, err
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "transport":
+			cfg.Transport.Transport = f.Transport.Transport
+		case "addr":
+			cfg.Transport.Addr = f.Transport.Addr
+		case "base-path":
+			cfg.Transport.BasePath = f.Transport.BasePath
+		case "share-server-token":
+			cfg.Transport.ShareServerToken = f.Transport.ShareServerToken
+		case "read-only":
+			cfg.ReadOnly = f.ReadOnly
+		case "dry-run":
+			cfg.DryRun = f.DryRun
+		case "toolsets":
+			cfg.Toolsets = parseToolsets(toolsets)
+		case "auth":
+			cfg.GitHub.Auth = f.GitHub.Auth
+		case "per-page":
+			cfg.Pagination.PerPage = f.Pagination.PerPage
+		case "max-retries":
+			cfg.Retry.MaxRetries = f.Retry.MaxRetries
+		case "retry-max-wait":
+			cfg.Retry.MaxWait = f.Retry.MaxWait
+		case "cache-max-mb":
+			cfg.Cache.MaxMB = f.Cache.MaxMB
+		case "cache-ttl":
+			cfg.Cache.TTL = f.Cache.TTL
+		case "audit-log":
+			cfg.Audit.Path = f.Audit.Path
+		case "audit-log-max-mb":
+			cfg.Audit.MaxMB = f.Audit.MaxMB
+		case "audit-log-max-files":
+			cfg.Audit.MaxFiles = f.Audit.MaxFiles
+		case "record":
+			cfg.Record = f.Record
+		}
+	})
+
+	return cfg, cfg.validate()
+}
+
+// loadFile reads a YAML file, or a JSON file when the name ends in .json,
+// over c. Unknown keys are rejected so typos do not go unnoticed.
+func (c *serverConfig) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		// Decode JSON generically and re-encode it so both formats share the YAML field names
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(v); err != nil {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	c.Toolsets = parseToolsets(strings.Join(c.Toolsets, ","))
+	return nil
+}
+
+// applyEnv overrides c with the environment variables that are set
+func (c *serverConfig) applyEnv() error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	list := func(key string, dst *[]string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = splitList(v)
+		}
+	}
+	boolean := func(key string, dst *bool) {
+		if v := os.Getenv(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected true or false", key, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	duration := func(key string, dst *time.Duration) {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a duration such as 10m", key, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	integer := func(key string, dst *int64) {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: expected a number", key, v))
+				return
+			}
+			*dst = n
+		}
+	}
+
+	str("MCP_TRANSPORT", &c.Transport.Transport)
+	str("MCP_ADDR", &c.Transport.Addr)
+	str("MCP_BASE_PATH", &c.Transport.BasePath)
+	boolean("MCP_SHARE_SERVER_TOKEN", &c.Transport.ShareServerToken)
+	boolean("MCP_READ_ONLY", &c.ReadOnly)
+	boolean("MCP_DRY_RUN", &c.DryRun)
+	if v := os.Getenv("MCP_TOOLSETS"); v != "" {
+		c.Toolsets = parseToolsets(v)
+	}
+
+	str("GITHUB_TOKEN", &c.GitHub.Token)
+	str("MCP_AUTH", &c.GitHub.Auth)
+	str("GITHUB_ENTERPRISE_URL", &c.GitHub.Enterprise.BaseURL)
+	str("GITHUB_ENTERPRISE_UPLOAD_URL", &c.GitHub.Enterprise.UploadURL)
+	str("GITHUB_ENTERPRISE_TOKEN", &c.GitHub.Enterprise.Token)
+	boolean("GITHUB_ENTERPRISE_SHARE_TOKEN", &c.GitHub.Enterprise.ShareToken)
+	list("GITHUB_ENTERPRISE_OWNERS", &c.GitHub.Enterprise.Owners)
+	integer("GITHUB_APP_ID", &c.GitHub.App.ID)
+	str("GITHUB_APP_PRIVATE_KEY", &c.GitHub.App.PrivateKey)
+	str("GITHUB_APP_PRIVATE_KEY_PATH", &c.GitHub.App.PrivateKeyPath)
+	integer("GITHUB_APP_INSTALLATION_ID", &c.GitHub.App.InstallationID)
+
+	var perPage int64
+	if integer("MCP_PER_PAGE", &perPage); perPage != 0 {
+		c.Pagination.PerPage = int(perPage)
+	}
+
+	retries := int64(c.Retry.MaxRetries)
+	integer("MCP_MAX_RETRIES", &retries)
+	c.Retry.MaxRetries = int(retries)
+	duration("MCP_RETRY_MAX_WAIT", &c.Retry.MaxWait)
+
+	cacheMB := int64(c.Cache.MaxMB)
+	integer("MCP_CACHE_MAX_MB", &cacheMB)
+	c.Cache.MaxMB = int(cacheMB)
+	duration("MCP_CACHE_TTL", &c.Cache.TTL)
+
+	list("MCP_POLICY_READ_ALLOW", &c.Policy.Read.Allow)
+	list("MCP_POLICY_READ_DENY", &c.Policy.Read.Deny)
+	list("MCP_POLICY_WRITE_ALLOW", &c.Policy.Write.Allow)
+	list("MCP_POLICY_WRITE_DENY", &c.Policy.Write.Deny)
+
+	str("MCP_AUDIT_LOG", &c.Audit.Path)
+	str("GITHUB_RECORD_CASSETTE", &c.Record)
+
+	return errors.Join(errs...)
+}
+
+// validate reports every setting the server cannot start with
+func (c serverConfig) validate() error {
+	var errs []error
+	switch strings.ToLower(c.Transport.Transport) {
+	case transportStdio, transportSSE, transportHTTP, "streamable-http":
+	default:
+		errs = append(errs, fmt.Errorf("unknown transport %q (expected stdio, sse or http)", c.Transport.Transport))
+	}
+	if err := c.serverOptions().validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Transport.multiTenant() && contains(c.Toolsets, toolsetAudit) {
+		errs = append(errs, fmt.Errorf("the audit toolset is only available over stdio, not the %s transport", c.Transport.Transport))
+	}
+	if c.Retry.MaxRetries < 0 || c.Retry.MaxWait <= 0 {
+		errs = append(errs, errors.New("retry max_retries must not be negative and max_wait must be positive"))
+	}
+	if c.Cache.MaxMB < 0 || c.Cache.TTL <= 0 {
+		errs = append(errs, errors.New("cache max_mb must not be negative and ttl must be positive"))
+	}
+	if c.Audit.MaxMB < 1 || c.Audit.MaxFiles < 1 {
+		errs = append(errs, errors.New("audit max_mb and max_files must be at least 1"))
+	}
+	if _, err := c.githubConfig(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// serverOptions returns the tool selection of c. The audit log is opened separately.
+func (c serverConfig) serverOptions() serverOptions {
+	return serverOptions{
+		Name:     c.Server.Name,
+		Version:  c.Server.Version,
+		ReadOnly: c.ReadOnly,
+		DryRun:   c.DryRun,
+		Toolsets: c.Toolsets,
+		// HTTP callers bring their own tokens and must not see each other's calls
+		MultiTenant: c.Transport.multiTenant(),
+	}
+}
+
+// githubConfig builds the tools.Config for c, loading the GitHub App key if one is used
+func (c serverConfig) githubConfig() (tools.Config, error) {
+	cfg := tools.Config{
+		BaseURL:    c.GitHub.BaseURL,
+		Enterprise: c.GitHub.Enterprise,
+		Policy:     c.Policy,
+		PerPage:    c.Pagination.PerPage,
+		Retry:      tools.RetryPolicy{MaxRetries: c.Retry.MaxRetries, MaxWait: c.Retry.MaxWait},
+		Cache:      tools.CacheConfig{MaxBytes: int64(c.Cache.MaxMB) << 20, TTL: c.Cache.TTL},
+		Priority:   c.Priority,
+	}
+	if c.Retry.MaxRetries == 0 {
+		cfg.Retry.MaxRetries = -1
+	}
+	if c.Cache.MaxMB == 0 {
+		cfg.Cache.MaxBytes = -1
+	}
+
+	app := c.GitHub.App
+	switch c.GitHub.Auth {
+	case authAuto:
+		cfg.Token = c.GitHub.Token
+	case authToken:
+		if c.GitHub.Token == "" {
+			return tools.Config{}, errors.New("auth is token but no GitHub token is configured")
+		}
+		cfg.Token, app = c.GitHub.Token, appConfig{}
+	case authApp:
+		if app.ID == 0 {
+			return tools.Config{}, errors.New("auth is app but no GitHub App id is configured")
+		}
+	case authNone:
+		cfg.Enterprise.Token, cfg.Enterprise.ShareToken, app = "", false, appConfig{}
+	default:
+		return tools.Config{}, fmt.Errorf("unknown auth %q (expected token, app or none)", c.GitHub.Auth)
+	}
+
+	if app.ID != 0 {
+		key := []byte(app.PrivateKey)
+		if len(key) == 0 && app.PrivateKeyPath != "" {
+			var err error
+			if key, err = os.ReadFile(app.PrivateKeyPath); err != nil {
+				return tools.Config{}, fmt.Errorf("failed to read GitHub App private key: %w", err)
+			}
+		}
+		if len(key) == 0 {
+			return tools.Config{}, errors.New("a GitHub App id is configured but neither private_key nor private_key_path is")
+		}
+		auth, err := tools.NewAppAuth(app.ID, key)
+		if err != nil {
+			return tools.Config{}, err
+		}
+		auth.InstallationID = app.InstallationID
+		cfg.App = auth
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
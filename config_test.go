@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// clearConfigEnv unsets the environment variables parseConfig reads for the duration of t
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"MCP_CONFIG", "MCP_TRANSPORT", "MCP_ADDR", "MCP_BASE_PATH", "MCP_SHARE_SERVER_TOKEN",
+		"MCP_READ_ONLY", "MCP_DRY_RUN", "MCP_TOOLSETS", "MCP_AUTH", "MCP_PER_PAGE", "MCP_AUDIT_LOG",
+		"MCP_CACHE_MAX_MB", "MCP_CACHE_TTL", "MCP_MAX_RETRIES", "MCP_RETRY_MAX_WAIT",
+		"GITHUB_TOKEN", "GITHUB_ENTERPRISE_URL", "GITHUB_ENTERPRISE_UPLOAD_URL", "GITHUB_ENTERPRISE_TOKEN",
+		"GITHUB_ENTERPRISE_OWNERS", "GITHUB_ENTERPRISE_SHARE_TOKEN", "GITHUB_APP_ID", "GITHUB_APP_PRIVATE_KEY", "GITHUB_APP_PRIVATE_KEY_PATH",
+		"GITHUB_APP_INSTALLATION_ID", "MCP_POLICY_READ_ALLOW", "MCP_POLICY_READ_DENY",
+		"MCP_POLICY_WRITE_ALLOW", "MCP_POLICY_WRITE_DENY", "GITHUB_RECORD_CASSETTE",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestParseConfigDefaults(t *testing.T) {
+	clearConfigEnv(t)
+
+	cfg, err := parseConfig("test", nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultConfig(), cfg)
+}
+
+func TestParseConfigExampleFile(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "ghes-token")
+
+	cfg, err := parseConfig("test", []string{"--config", "config.example.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, transportHTTP, cfg.Transport.Transport)
+	assert.Equal(t, []string{"platform", "infra"}, cfg.GitHub.Enterprise.Owners)
+	assert.Equal(t, 50, cfg.Pagination.PerPage)
+	assert.Equal(t, 25, cfg.Priority.Critical)
+	assert.Equal(t, []string{"acme/widgets"}, cfg.Policy.Write.Allow)
+	assert.Equal(t, []string{toolsetIssues, toolsetPulls, toolsetSearch, toolsetAnalytics, toolsetWrites}, cfg.Toolsets)
+}
+
+func TestParseConfigJSON(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfig(t, "config.json", `{
+	"transport": {"type": "sse", "addr": ":9090"},
+	"toolsets": ["all"],
+	"priority": {"limit": 50}
+}`)
+
+	cfg, err := parseConfig("test", []string{"--config", path})
+	require.NoError(t, err)
+	assert.Equal(t, transportSSE, cfg.Transport.Transport)
+	assert.Equal(t, ":9090", cfg.Transport.Addr)
+	assert.Equal(t, "/mcp", cfg.Transport.BasePath, "unset keys keep their defaults")
+	assert.Empty(t, cfg.Toolsets)
+	assert.Equal(t, 50, cfg.Priority.Limit)
+}
+
+func TestParseConfigPrecedence(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfig(t, "config.yaml", "transport:\n  type: sse\n  addr: ':9090'\nread_only: true\npagination:\n  per_page: 20\n")
+	t.Setenv("MCP_CONFIG", path)
+	t.Setenv("MCP_ADDR", ":7070")
+	t.Setenv("MCP_READ_ONLY", "false")
+	t.Setenv("GITHUB_TOKEN", "env-token")
+
+	cfg, err := parseConfig("test", []string{"--per-page", "30"})
+	require.NoError(t, err)
+	assert.Equal(t, transportSSE, cfg.Transport.Transport, "file")
+	assert.Equal(t, ":7070", cfg.Transport.Addr, "env overrides file")
+	assert.False(t, cfg.ReadOnly, "env overrides file")
+	assert.Equal(t, 30, cfg.Pagination.PerPage, "flag overrides file")
+	assert.Equal(t, "env-token", cfg.GitHub.Token)
+
+	cfg, err = parseConfig("test", []string{"--addr", ":6060"})
+	require.NoError(t, err)
+	assert.Equal(t, ":6060", cfg.Transport.Addr, "flag overrides env")
+}
+
+func TestParseConfigValidation(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"unknown key", "transprot:\n  type: http\n", "field transprot not found"},
+		{"transport", "transport:\n  type: carrier-pigeon\n", `unknown transport "carrier-pigeon"`},
+		{"toolset", "toolsets: [issues, bogus]\n", `unknown toolset "bogus"`},
+		{"per page", "pagination:\n  per_page: 500\n", "per_page must be between 1 and 100"},
+		{"thresholds", "priority:\n  critical: 5\n  high: 10\n", "critical >= high >= medium"},
+		{"policy", "policy:\n  read:\n    allow: ['acme/[']\n", "invalid policy pattern"},
+		{"retry", "retry:\n  max_retries: -1\n", "max_retries must not be negative"},
+		{"cache", "cache:\n  ttl: 0s\n", "ttl must be positive"},
+		{"auth", "github:\n  auth: magic\n", `unknown auth "magic"`},
+		{"auth token", "github:\n  auth: token\n", "no GitHub token is configured"},
+		{"app key", "github:\n  app:\n    id: 42\n", "neither private_key nor private_key_path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			path := writeConfig(t, "config.yaml", tt.content)
+
+			_, err := parseConfig("test", []string{"--config", path})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestParseConfigCache(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfig(t, "config.yaml", "cache:\n  max_mb: 8\n  ttl: 2m\n")
+	t.Setenv("MCP_CACHE_MAX_MB", "64")
+
+	cfg, err := parseConfig("test", []string{"--config", path, "--cache-ttl", "30s"})
+	require.NoError(t, err)
+	githubCfg, err := cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Equal(t, tools.CacheConfig{MaxBytes: 64 << 20, TTL: 30 * time.Second}, githubCfg.Cache)
+
+	cfg, err = parseConfig("test", []string{"--cache-max-mb", "0"})
+	require.NoError(t, err)
+	githubCfg, err = cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Negative(t, githubCfg.Cache.MaxBytes, "a size of 0 turns the cache off")
+
+	t.Setenv("MCP_CACHE_TTL", "soon")
+	_, err = parseConfig("test", nil)
+	assert.ErrorContains(t, err, "invalid MCP_CACHE_TTL")
+}
+
+func TestParseConfigRetry(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfig(t, "config.yaml", "retry:\n  max_retries: 5\n  max_wait: 2m\n")
+	t.Setenv("MCP_RETRY_MAX_WAIT", "90s")
+
+	cfg, err := parseConfig("test", []string{"--config", path})
+	require.NoError(t, err)
+	githubCfg, err := cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Equal(t, tools.RetryPolicy{MaxRetries: 5, MaxWait: 90 * time.Second}, githubCfg.Retry)
+
+	cfg, err = parseConfig("test", []string{"--max-retries", "0"})
+	require.NoError(t, err)
+	githubCfg, err = cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Negative(t, githubCfg.Retry.MaxRetries, "0 retries turns retrying off")
+}
+
+func TestToolsetsFlagListsEveryToolset(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	configFlags(fs)
+	for _, name := range allToolsets {
+		assert.Contains(t, fs.Lookup("toolsets").Usage, name)
+	}
+}
+
+func TestParseConfigInvalidEnv(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("MCP_READ_ONLY", "yes please")
+
+	_, err := parseConfig("test", nil)
+	assert.ErrorContains(t, err, "invalid MCP_READ_ONLY")
+}
+
+func TestGitHubConfigAuth(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.GitHub.Token = "server-token"
+	cfg.GitHub.Enterprise.Token = "ghes-token"
+
+	githubCfg, err := cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "server-token", githubCfg.Token)
+
+	cfg.GitHub.Auth = authNone
+	githubCfg, err = cfg.githubConfig()
+	require.NoError(t, err)
+	assert.Empty(t, githubCfg.Token)
+	assert.Empty(t, githubCfg.Enterprise.Token)
+}
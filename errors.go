@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// Stable codes reported with every tool error, in the result's _meta.error_code
+const (
+	errCodeInvalidArguments = "invalid_arguments"
+	errCodeReadOnly         = "read_only"
+	errCodePolicyDenied     = "policy_denied"
+	errCodeUnauthorized     = "unauthorized"
+	errCodeForbidden        = "forbidden"
+	errCodeNotFound         = "not_found"
+	errCodeValidation       = "validation_failed"
+	errCodeRateLimited      = "rate_limited"
+	errCodeGitHub           = "github_error"
+	errCodeRequestFailed    = "request_failed"
+)
+
+// toolError is a classified tool failure
+type toolError struct {
+	Code    string
+	Message string
+	// Hint tells the caller what to do about the error, if anything
+	Hint string
+}
+
+func (e *toolError) Error() string {
+	return e.Message
+}
+
+// errorResult reports err to the client as a tool result with IsError set,
+// so the model sees the failure instead of the JSON-RPC call breaking
+func errorResult(err error) *mcp.CallToolResult {
+	te := classifyError(err)
+
+	text := fmt.Sprintf("❌ %s\n\nError code: %s", te.Message, te.Code)
+	if te.Hint != "" {
+		text += "\nHint: " + te.Hint
+	}
+
+	result := mcp.NewToolResultError(text)
+	result.Meta = &mcp.Meta{AdditionalFields: map[string]any{"error_code": te.Code}}
+	return result
+}
+
+// resourceError describes err for a failed resource read, which MCP reports
+// as a JSON-RPC error rather than a result
+func resourceError(err error) error {
+	te := classifyError(err)
+	msg := fmt.Sprintf("%s (%s)", te.Message, te.Code)
+	if te.Hint != "" {
+		msg += ": " + te.Hint
+	}
+	return errors.New(msg)
+}
+
+// errorCode returns the code errorResult attached to result, or ""
+func errorCode(result *mcp.CallToolResult) string {
+	if result == nil || result.Meta == nil {
+		return ""
+	}
+	code, _ := result.Meta.AdditionalFields["error_code"].(string)
+	return code
+}
+
+// classifyError maps errors from the tools package and go-github to a stable code and hint
+func classifyError(err error) *toolError {
+	var (
+		te         *toolError
+		input      *tools.InputError
+		policy     *tools.PolicyError
+		limited    *tools.RateLimitedError
+		rateLimit  *github.RateLimitError
+		abuseLimit *github.AbuseRateLimitError
+		response   *github.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &te):
+		return te
+	case errors.As(err, &input):
+		return &toolError{Code: errCodeInvalidArguments, Message: err.Error(),
+			Hint: "fix the arguments to match the tool's input schema and call it again"}
+	case errors.As(err, &policy):
+		return &toolError{Code: errCodePolicyDenied, Message: err.Error(),
+			Hint: "the server's repository policy does not allow this; use another repository or ask the operator to change the policy"}
+	case errors.As(err, &limited), errors.As(err, &rateLimit), errors.As(err, &abuseLimit):
+		msg := err.Error()
+		if limited != nil {
+			msg = limited.Error()
+		}
+		return &toolError{Code: errCodeRateLimited, Message: msg,
+			Hint: "wait for the limit to reset before retrying; authenticated requests get a much higher limit"}
+	case errors.As(err, &response) && response.Response != nil:
+		return classifyResponse(err, response)
+	default:
+		return &toolError{Code: errCodeRequestFailed, Message: err.Error()}
+	}
+}
+
+// classifyResponse classifies an error status returned by GitHub
+func classifyResponse(err error, response *github.ErrorResponse) *toolError {
+	status := response.Response.StatusCode
+	msg := fmt.Sprintf("GitHub returned %d %s", status, http.StatusText(status))
+	if response.Message != "" {
+		msg += ": " + response.Message
+	}
+	// Keep any context the tools package added, e.g. "failed to create issue: "
+	if prefix, _, ok := strings.Cut(err.Error(), response.Error()); ok {
+		msg = prefix + msg
+	}
+
+	switch status {
+	case http.StatusUnauthorized:
+		return &toolError{Code: errCodeUnauthorized, Message: msg,
+			Hint: "the GitHub token is missing, invalid or expired; set GITHUB_TOKEN or send a valid Authorization: Bearer header"}
+	case http.StatusForbidden:
+		return &toolError{Code: errCodeForbidden, Message: msg, Hint: forbiddenHint(response.Response.Header)}
+	case http.StatusNotFound:
+		return &toolError{Code: errCodeNotFound, Message: msg,
+			Hint: "repository not found or private: check owner and repo, and that the token can access private repositories"}
+	case http.StatusUnprocessableEntity:
+		var details []string
+		for _, e := range response.Errors {
+			switch {
+			case e.Message != "":
+				details = append(details, e.Message)
+			case e.Field != "":
+				details = append(details, fmt.Sprintf("%s is %s", e.Field, e.Code))
+			}
+		}
+		hint := "GitHub rejected the input; check the values and try again"
+		if len(details) > 0 {
+			hint = "GitHub rejected the input: " + strings.Join(details, "; ")
+		}
+		return &toolError{Code: errCodeValidation, Message: msg, Hint: hint}
+	default:
+		hint := ""
+		if status >= 500 {
+			hint = "GitHub is having problems; try again shortly"
+		}
+		return &toolError{Code: errCodeGitHub, Message: msg, Hint: hint}
+	}
+}
+
+// forbiddenHint explains a 403 from the SSO and OAuth scope headers GitHub sends
+func forbiddenHint(header http.Header) string {
+	if header.Get("X-GitHub-SSO") != "" {
+		return "the token must be authorized for the organization's SAML single sign-on"
+	}
+
+	// Classic tokens report their scopes, and any one accepted scope is enough
+	if granted := header.Get("X-OAuth-Scopes"); granted != "" {
+		has := make(map[string]bool)
+		for _, scope := range strings.Split(granted, ",") {
+			has[strings.TrimSpace(scope)] = true
+		}
+		var accepted []string
+		for _, scope := range strings.Split(header.Get("X-Accepted-OAuth-Scopes"), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				if has[scope] {
+					accepted = nil
+					break
+				}
+				accepted = append(accepted, scope)
+			}
+		}
+		if len(accepted) > 0 {
+			return fmt.Sprintf("token lacks %s scope", strings.Join(accepted, " or "))
+		}
+	}
+
+	return "the token lacks permission for this repository: classic tokens need the repo scope, fine-grained tokens need Issues or Pull requests access"
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// githubError builds the error go-github returns for a response with status and header
+func githubError(status int, header http.Header, details ...github.Error) error {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/acme/widgets/issues", nil)
+	return &github.ErrorResponse{
+		Response: &http.Response{StatusCode: status, Header: header, Request: req},
+		Message:  http.StatusText(status),
+		Errors:   details,
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+		hint string
+	}{
+		{"unauthorized", githubError(401, nil), errCodeUnauthorized, "token is missing, invalid or expired"},
+		{"missing scope", githubError(403, http.Header{"X-Oauth-Scopes": {"read:org"}, "X-Accepted-Oauth-Scopes": {"repo"}}),
+			errCodeForbidden, "token lacks repo scope"},
+		{"scope granted", githubError(403, http.Header{"X-Oauth-Scopes": {"repo, read:org"}, "X-Accepted-Oauth-Scopes": {"public_repo, repo"}}),
+			errCodeForbidden, "lacks permission for this repository"},
+		{"sso", githubError(403, http.Header{"X-Github-Sso": {"required; url=https://github.com/orgs/acme/sso"}}),
+			errCodeForbidden, "SAML single sign-on"},
+		{"not found", githubError(404, nil), errCodeNotFound, "repository not found or private"},
+		{"validation", githubError(422, nil, github.Error{Field: "assignee", Code: "invalid"}),
+			errCodeValidation, "GitHub rejected the input: assignee is invalid"},
+		{"server error", githubError(502, nil), errCodeGitHub, "try again shortly"},
+		{"rate limited", &tools.RateLimitedError{Until: time.Date(2025, 6, 1, 13, 0, 0, 0, time.UTC)},
+			errCodeRateLimited, "wait for the limit to reset"},
+		{"policy", &tools.PolicyError{Operation: "write", Owner: "acme", Repo: "widgets", Reason: "nope"},
+			errCodePolicyDenied, "repository policy"},
+		{"wrapped", fmt.Errorf("failed to create issue: %w", githubError(404, nil)), errCodeNotFound, ""},
+		{"network", errors.New("dial tcp: connection refused"), errCodeRequestFailed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te := classifyError(tt.err)
+			assert.Equal(t, tt.code, te.Code)
+			assert.Contains(t, te.Hint, tt.hint)
+		})
+	}
+}
+
+func TestClassifyErrorKeepsContext(t *testing.T) {
+	te := classifyError(fmt.Errorf("failed to create issue: %w", githubError(404, nil)))
+	assert.Equal(t, "failed to create issue: GitHub returned 404 Not Found: Not Found", te.Message)
+}
+
+func TestErrorResult(t *testing.T) {
+	result := errorResult(githubError(404, nil))
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeNotFound, errorCode(result))
+	assert.Contains(t, resultText(t, result), "Error code: not_found")
+}
@@ -4,20 +4,24 @@ go 1.24.1
 
 require (
 	github.com/google/go-github/v56 v56.0.0
+	github.com/invopop/jsonschema v0.13.0
 	github.com/stretchr/testify v1.10.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/bahlo/generic-list-go v0.2.0 // indirect
+	github.com/buger/jsonparser v1.1.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/cast v1.7.1 // indirect
+	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
 	github.com/google/go-querystring v1.1.0 // indirect
-	github.com/mark3labs/mcp-go v0.29.0
-	golang.org/x/oauth2 v0.30.0
+	github.com/mark3labs/mcp-go v0.44.0
 )
@@ -3,8 +3,8 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
@@ -15,202 +15,238 @@ import (
 )
 
 func main() {
-	s := server.NewMCPServer(
-		"GitHub MCP Server",
-		"0.1.0",
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// newMCPServer creates the MCP server with the tools enabled by opts registered against svc
+func newMCPServer(svc *tools.Service, opts serverOptions) *server.MCPServer {
+	h := &handlers{github: svc, readOnly: opts.ReadOnly, dryRun: opts.DryRun}
+
+	// mutating lists the registered tools that can modify GitHub, for the audit log
+	mutating := make(map[string]bool)
+	completions := completionProvider{svc: svc}
+	serverOpts := []server.ServerOption{
 		server.WithToolCapabilities(false),
-	)
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completions),
+		server.WithResourceCompletionProvider(completions),
+		server.WithToolHandlerMiddleware(progressMiddleware),
+	}
+	if opts.Audit != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(opts.Audit.middleware(svc, mutating)))
+	}
+
+	name, version := opts.Name, opts.Version
+	if name == "" {
+		name = "GitHub MCP Server"
+	}
+	if version == "" {
+		version = "0.1.0"
+	}
+	s := server.NewMCPServer(name, version, serverOpts...)
 
-	// Define the enhanced tools
+	// Define the tools; each tool's arguments come from its input type in the tools package
 	listPRsTool := mcp.NewTool("list_prs",
 		mcp.WithDescription("List pull requests in a GitHub repository"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
-		mcp.WithString("state",
-			mcp.Description("State of PRs to list (open, closed, all). Defaults to open"),
-		),
+		withInput[tools.ListPullRequestsInput](),
+		mcp.WithOutputSchema[pullRequestListResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 
 	listIssuestool := mcp.NewTool("list_issues",
 		mcp.WithDescription("List issues in a GitHub repository"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
-		mcp.WithString("state",
-			mcp.Description("State of issues to list (open, closed, all). Defaults to open"),
-		),
+		withInput[tools.ListIssuesInput](),
+		mcp.WithOutputSchema[issueListResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 
 	searchIssuesTool := mcp.NewTool("search_issues",
 		mcp.WithDescription("Search issues by keyword/topic and analyze priority"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
-		mcp.WithString("query",
-			mcp.Required(),
-			mcp.Description("Search query/topic to filter issues"),
-		),
-		mcp.WithString("state",
-			mcp.Description("State of issues to search (open, closed, all). Defaults to open"),
-		),
-		mcp.WithBoolean("prioritize",
-			mcp.Description("Whether to analyze and sort by priority. Defaults to false"),
-		),
+		withInput[tools.SearchIssuesInput](),
+		mcp.WithOutputSchema[searchIssuesResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 
 	pendingReviewsTool := mcp.NewTool("get_pending_reviews",
 		mcp.WithDescription("Get pull requests pending review"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
+		withInput[tools.PendingReviewsInput](),
+		mcp.WithOutputSchema[pullRequestListResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 
 	createIssueTool := mcp.NewTool("create_issue",
 		mcp.WithDescription("Create a new GitHub issue (useful for K8s diagnostic integration)"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
-		mcp.WithString("title",
-			mcp.Required(),
-			mcp.Description("Issue title"),
-		),
-		mcp.WithString("body",
-			mcp.Description("Issue body/description"),
-		),
-		mcp.WithString("labels",
-			mcp.Description("Comma-separated labels to apply to the issue"),
-		),
-		mcp.WithString("assignee",
-			mcp.Description("Username to assign the issue to"),
-		),
+		withInput[tools.CreateIssueInput](),
+		mcp.WithOutputSchema[createIssueResult](),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
 	)
 
 	priorityTool := mcp.NewTool("analyze_issue_priority",
 		mcp.WithDescription("Analyze and rank issues by priority based on comments, reactions, labels"),
-		mcp.WithString("owner",
-			mcp.Required(),
-			mcp.Description("GitHub org or user"),
-		),
-		mcp.WithString("repo",
-			mcp.Required(),
-			mcp.Description("GitHub repository name"),
-		),
-		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of issues to analyze. Defaults to 20"),
-		),
+		withInput[tools.PriorityInput](),
+		mcp.WithOutputSchema[priorityAnalysisResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
 	)
 
-	// Register the tools with their handlers
-	s.AddTool(listPRsTool, listOpenPRsHandler)
-	s.AddTool(listIssuestool, listOpenIssuesHandler)
-	s.AddTool(searchIssuesTool, searchIssuesHandler)
-	s.AddTool(pendingReviewsTool, getPendingReviewsHandler)
-	s.AddTool(createIssueTool, createIssueHandler)
-	s.AddTool(priorityTool, analyzePriorityHandler)
+	auditLogTool := mcp.NewTool("get_audit_log",
+		mcp.WithDescription("Read recent entries of the server's audit log of tool calls, newest first"),
+		withInput[auditLogInput](),
+		mcp.WithOutputSchema[auditLogResult](),
+		mcp.WithReadOnlyHintAnnotation(true),
+	)
 
-	// Run the MCP server
-	if err := server.ServeStdio(s); err != nil {
-		fmt.Printf("Server error: %v\n", err)
+	// Register the tools of every enabled toolset with their handlers
+	for _, t := range []toolsetTool{
+		{toolsetPulls, listPRsTool, h.listOpenPRsHandler},
+		{toolsetIssues, listIssuestool, h.listOpenIssuesHandler},
+		{toolsetSearch, searchIssuesTool, h.searchIssuesHandler},
+		{toolsetPulls, pendingReviewsTool, h.getPendingReviewsHandler},
+		{toolsetWrites, createIssueTool, h.createIssueHandler},
+		{toolsetAnalytics, priorityTool, h.analyzePriorityHandler},
+		{toolsetAudit, auditLogTool, auditLogHandler(opts.Audit)},
+	} {
+		if opts.enabled(t.toolset) {
+			s.AddTool(t.tool, t.handler)
+			mutating[t.tool.Name] = isMutating(t.tool)
+		}
+	}
+	if opts.enabled(toolsetIssues) {
+		s.AddResourceTemplates(issueResourceTemplate(svc))
+	}
+	if opts.enabled(toolsetPulls) {
+		s.AddResourceTemplates(pullResourceTemplate(svc))
+	}
+	if opts.enabled(toolsetContents) {
+		s.AddResourceTemplates(repoResourceTemplates(svc)...)
+	}
+
+	// A prompt is offered when every toolset it relies on is enabled
+	for _, p := range workflowPrompts(svc, opts) {
+		enabled := true
+		for _, toolset := range p.toolsets {
+			enabled = enabled && opts.enabled(toolset)
+		}
+		if enabled {
+			s.AddPrompt(p.prompt, p.handler)
+		}
+	}
+
+	return s
+}
+
+// withInput sets a tool's input schema to the one reflected from its input type T,
+// which is also what tools.DecodeInput validates calls against
+func withInput[T any]() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		if err := json.Unmarshal(tools.InputSchema[T](), &t.InputSchema); err != nil {
+			panic(fmt.Sprintf("input schema of %s: %v", t.Name, err))
+		}
 	}
 }
 
-// listOpenIssuesHandler converts MCP input into raw JSON and delegates to tools.GetOpenIssues
-func listOpenIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// nextCursorNote tells the caller how to fetch the next page, if there is one
+func nextCursorNote(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nMore results available. next_cursor: %s\n", cursor)
+}
+
+// priorityCategories maps the categories of tools.Service.AnalyzeIssuePriority to their headings
+var priorityCategories = []struct{ name, heading string }{
+	{tools.PriorityCritical, "🔴 critical"},
+	{tools.PriorityHigh, "🟡 high"},
+	{tools.PriorityMedium, "🟢 medium"},
+	{tools.PriorityLow, "⚪ low"},
+}
+
+// handlers adapts MCP tool calls onto the shared GitHub service
+type handlers struct {
+	github   *tools.Service
+	readOnly bool
+	dryRun   bool
+}
+
+// listOpenIssuesHandler converts MCP input into raw JSON and delegates to tools.Service.GetOpenIssues
+func (h *handlers) listOpenIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return nil, errors.New("failed to marshal arguments")
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	issues, err := tools.GetOpenIssues(ctx, raw)
+	issues, next, err := h.github.GetOpenIssues(ctx, raw)
 	if err != nil {
-		return nil, err
+		return errorResult(err), nil
 	}
 
+	result := issueListResult{Issues: newIssueResults(issues), NextCursor: next}
 	if len(issues) == 0 {
-		return mcp.NewToolResultText("No open issues found."), nil
+		return mcp.NewToolResultStructured(result, "No open issues found."+nextCursorNote(next)), nil
 	}
 
 	var output string
 	for _, issue := range issues {
 		output += fmt.Sprintf("- #%d: %s\n", issue.GetNumber(), issue.GetTitle())
 	}
+	output += nextCursorNote(next)
 
-	return mcp.NewToolResultText(output), nil
+	return mcp.NewToolResultStructured(result, output), nil
 }
 
-// listOpenPRsHandler converts MCP input into raw JSON and delegates to tools.GetOpenPRs
-func listOpenPRsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// listOpenPRsHandler converts MCP input into raw JSON and delegates to tools.Service.GetOpenPRs
+func (h *handlers) listOpenPRsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return nil, errors.New("failed to marshal arguments")
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	prList, err := tools.GetOpenPRs(ctx, raw)
+	prList, next, err := h.github.GetOpenPRs(ctx, raw)
 	if err != nil {
-		return nil, err
+		return errorResult(err), nil
 	}
 
+	result := pullRequestListResult{PullRequests: newPullRequestResults(prList), NextCursor: next}
 	if len(prList) == 0 {
-		return mcp.NewToolResultText("No open pull requests found."), nil
+		return mcp.NewToolResultStructured(result, "No open pull requests found."+nextCursorNote(next)), nil
 	}
 
 	var output string
 	for _, pr := range prList {
 		output += fmt.Sprintf("- #%d: %s\n", pr.GetNumber(), pr.GetTitle())
 	}
+	output += nextCursorNote(next)
 
-	return mcp.NewToolResultText(output), nil
+	return mcp.NewToolResultStructured(result, output), nil
 }
 
+// search_issues ranks prioritized results by comments plus reactions alone,
+// a different scale from the analyze_issue_priority score, so its buckets
+// are fixed rather than taken from the priority config
+const (
+	searchHighScore   = 10
+	searchMediumScore = 3
+)
+
 // searchIssuesHandler handles searching issues by topic/keyword with optional priority analysis
-func searchIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (h *handlers) searchIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return nil, errors.New("failed to marshal arguments")
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	issues, err := tools.SearchIssues(ctx, raw)
+	issues, next, err := h.github.SearchIssues(ctx, raw)
 	if err != nil {
-		return nil, err
+		return errorResult(err), nil
 	}
 
-	if len(issues) == 0 {
-		return mcp.NewToolResultText("No issues found matching the search criteria."), nil
-	}
+	// Check if prioritization was requested; SearchIssues has already validated the input
+	input, _ := tools.DecodeInput[tools.SearchIssuesInput](raw)
 
-	// Check if prioritization was requested
-	var input struct {
-		Query      string `json:"query"`
-		State      string `json:"state"`
-		Prioritize bool   `json:"prioritize"`
+	result := searchIssuesResult{Query: input.Query, Issues: []issueResult{}, NextCursor: next}
+	if len(issues) == 0 {
+		return mcp.NewToolResultStructured(result, "No issues found matching the search criteria."+nextCursorNote(next)), nil
 	}
-	json.Unmarshal(raw, &input)
 
 	var output strings.Builder
 	output.WriteString(fmt.Sprintf("Found %d issues related to '%s':\n\n", len(issues), input.Query))
@@ -235,13 +271,19 @@ func searchIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 			line := fmt.Sprintf("- #%d: %s %s(Score: %d - %d comments, %d reactions)",
 				issue.GetNumber(), issue.GetTitle(), labels, score, issue.GetComments(), issue.GetReactions().GetTotalCount())
 
-			if score >= 10 {
+			item := newIssueResult(issue)
+			item.Score = &score
+			if score >= searchHighScore {
 				high = append(high, line)
-			} else if score >= 3 {
+				item.Priority = "high"
+			} else if score >= searchMediumScore {
 				medium = append(medium, line)
+				item.Priority = "medium"
 			} else {
 				low = append(low, line)
+				item.Priority = "low"
 			}
+			result.Issues = append(result.Issues, item)
 		}
 
 		if len(high) > 0 {
@@ -269,25 +311,28 @@ func searchIssuesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 		for _, issue := range issues {
 			output.WriteString(fmt.Sprintf("- #%d: %s\n", issue.GetNumber(), issue.GetTitle()))
 		}
+		result.Issues = newIssueResults(issues)
 	}
+	output.WriteString(nextCursorNote(next))
 
-	return mcp.NewToolResultText(output.String()), nil
+	return mcp.NewToolResultStructured(result, output.String()), nil
 }
 
 // getPendingReviewsHandler gets PRs that are pending review
-func getPendingReviewsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (h *handlers) getPendingReviewsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return nil, errors.New("failed to marshal arguments")
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	prs, err := tools.GetPendingReviews(ctx, raw)
+	prs, err := h.github.GetPendingReviews(ctx, raw)
 	if err != nil {
-		return nil, err
+		return errorResult(err), nil
 	}
 
+	result := pullRequestListResult{PullRequests: newPullRequestResults(prs)}
 	if len(prs) == 0 {
-		return mcp.NewToolResultText("No pull requests pending review found."), nil
+		return mcp.NewToolResultStructured(result, "No pull requests pending review found."), nil
 	}
 
 	var output strings.Builder
@@ -304,36 +349,41 @@ func getPendingReviewsHandler(ctx context.Context, req mcp.CallToolRequest) (*mc
 		}
 	}
 
-	return mcp.NewToolResultText(output.String()), nil
+	return mcp.NewToolResultStructured(result, output.String()), nil
 }
 
 // createIssueHandler creates a new GitHub issue
-func createIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Safely cast Arguments to map[string]interface{}
-	args, ok := req.Params.Arguments.(map[string]interface{})
-	if !ok {
-		return mcp.NewToolResultText("❌ Invalid arguments format."), nil
-	}
-
-	// Convert labels string to []string if needed
-	if labels, ok := args["labels"]; ok {
-		if labelStr, ok := labels.(string); ok && labelStr != "" {
-			labelSlice := strings.Split(labelStr, ",")
-			for i, v := range labelSlice {
-				labelSlice[i] = strings.TrimSpace(v)
-			}
-			args["labels"] = labelSlice
-		}
+func (h *handlers) createIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if h.readOnly {
+		return errorResult(&toolError{Code: errCodeReadOnly, Message: "create_issue is disabled: the server is running in read-only mode",
+			Hint: "restart the server without --read-only to create issues"}), nil
 	}
 
-	raw, err := json.Marshal(args)
+	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to marshal arguments: %v", err)), nil
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	issue, err := tools.CreateIssue(ctx, raw)
+	input, err := tools.DecodeInput[tools.CreateIssueInput](raw)
 	if err != nil {
-		return mcp.NewToolResultText(fmt.Sprintf("❌ Failed to create issue: %v", err)), nil
+		return errorResult(err), nil
+	}
+
+	dryRun := h.dryRun
+	if input.DryRun != nil {
+		dryRun = *input.DryRun
+	}
+	if dryRun {
+		preview, err := h.github.PreviewIssue(ctx, raw)
+		if err != nil {
+			return errorResult(err), nil
+		}
+		return issuePreviewResult(input, preview), nil
+	}
+
+	issue, err := h.github.CreateIssue(ctx, raw)
+	if err != nil {
+		return errorResult(fmt.Errorf("failed to create issue: %w", err)), nil
 	}
 
 	output := fmt.Sprintf("✅ Issue created successfully!\n\n"+
@@ -346,36 +396,75 @@ func createIssueHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		issue.GetHTMLURL(),
 		issue.GetState())
 
-	return mcp.NewToolResultText(output), nil
+	result := newIssueResult(issue)
+	return mcp.NewToolResultStructured(createIssueResult{Issue: &result}, output), nil
+}
+
+// issuePreviewResult describes the request a dry-run create_issue would have sent
+func issuePreviewResult(input tools.CreateIssueInput, preview *tools.IssuePreview) *mcp.CallToolResult {
+	request, _ := json.MarshalIndent(preview.Request, "", "  ")
+
+	var output strings.Builder
+	output.WriteString("📝 Dry run: no issue was created.\n\n")
+	fmt.Fprintf(&output, "Request that would be sent to POST /repos/%s/%s/issues:\n\n```json\n%s\n```\n", input.Owner, input.Repo, request)
+	if len(preview.Warnings) > 0 {
+		output.WriteString("\n⚠️ Warnings:\n")
+		for _, warning := range preview.Warnings {
+			fmt.Fprintf(&output, "- %s\n", warning)
+		}
+	}
+
+	return mcp.NewToolResultStructured(createIssueResult{
+		DryRun:   true,
+		Request:  preview.Request,
+		Warnings: preview.Warnings,
+	}, output.String())
 }
 
 // analyzePriorityHandler analyzes issue priority based on engagement metrics
-func analyzePriorityHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (h *handlers) analyzePriorityHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	raw, err := json.Marshal(req.Params.Arguments)
 	if err != nil {
-		return nil, errors.New("failed to marshal arguments")
+		return errorResult(&toolError{Code: errCodeInvalidArguments, Message: "failed to marshal arguments"}), nil
 	}
 
-	analysis, err := tools.AnalyzeIssuePriority(ctx, raw)
+	analysis, err := h.github.AnalyzeIssuePriority(ctx, raw)
 	if err != nil {
-		return nil, err
-	}
-
-	if len(analysis) == 0 {
-		return mcp.NewToolResultText("No issues found for priority analysis."), nil
+		return errorResult(err), nil
 	}
 
+	result := priorityAnalysisResult{Issues: []issueResult{}}
 	var output strings.Builder
 	output.WriteString("📊 ISSUE PRIORITY ANALYSIS\n\n")
 
-	for category, issues := range analysis {
-		output.WriteString(fmt.Sprintf("%s (%d issues):\n", strings.ToUpper(category), len(issues)))
-		for _, issue := range issues {
+	// Categories are listed most urgent first
+	for _, category := range priorityCategories {
+		var issues []tools.ScoredIssue
+		for _, scored := range analysis {
+			if scored.Category == category.name {
+				issues = append(issues, scored)
+			}
+		}
+		if len(issues) == 0 {
+			continue
+		}
+		output.WriteString(fmt.Sprintf("%s (%d issues):\n", strings.ToUpper(category.heading), len(issues)))
+		for _, scored := range issues {
 			output.WriteString(fmt.Sprintf("- #%d: %s (Score: %d)\n",
-				issue["number"], issue["title"], issue["priority_score"]))
+				scored.Issue.GetNumber(), scored.Issue.GetTitle(), scored.Score))
+
+			item := newIssueResult(scored.Issue)
+			score := scored.Score
+			item.Score = &score
+			item.Priority = category.name
+			result.Issues = append(result.Issues, item)
 		}
 		output.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(output.String()), nil
+	if len(result.Issues) == 0 {
+		return mcp.NewToolResultStructured(result, "No issues found for priority analysis."), nil
+	}
+
+	return mcp.NewToolResultStructured(result, output.String()), nil
 }
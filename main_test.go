@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+	"github.com/himanshusharma89/github-mcp-server/tools/githubtest"
+)
+
+// newTestServer returns the MCP server backed by a fake GitHub seeded with acme/widgets
+func newTestServer(t *testing.T) (*server.MCPServer, *githubtest.Server) {
+	t.Helper()
+	return newTestServerWith(t, serverOptions{})
+}
+
+// newTestServerWith is newTestServer with the given tool selection
+func newTestServerWith(t *testing.T, opts serverOptions) (*server.MCPServer, *githubtest.Server) {
+	t.Helper()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := &github.Timestamp{Time: now.AddDate(0, 0, -3)}
+
+	fake := githubtest.NewServer(&githubtest.Repo{
+		Owner: "acme",
+		Name:  "widgets",
+		Issues: []*github.Issue{
+			{Number: github.Int(1), Title: github.String("Crash on startup"), State: github.String("open"),
+				Comments: github.Int(12), CreatedAt: created, Body: github.String("The app exits before the window opens."),
+				User:      &github.User{Login: github.String("octocat")},
+				Assignees: []*github.User{{Login: github.String("alice")}}},
+			{Number: github.Int(2), Title: github.String("Docs typo"), State: github.String("open"), CreatedAt: created,
+				Labels: []*github.Label{{Name: github.String("docs")}, {Name: github.String("good first issue")}}},
+		},
+		PullRequests: []*github.PullRequest{
+			{Number: github.Int(3), Title: github.String("Add feature"), State: github.String("open"), CreatedAt: created,
+				Body: github.String("Adds the feature."), User: &github.User{Login: github.String("carol")},
+				Head: &github.PullRequestBranch{Label: github.String("carol:feature")}, Base: &github.PullRequestBranch{Ref: github.String("main")}},
+			{Number: github.Int(4), Title: github.String("WIP"), State: github.String("open"), Draft: github.Bool(true), CreatedAt: created},
+		},
+		Reviews: map[int][]*github.PullRequestReview{
+			3: {
+				{State: github.String("CHANGES_REQUESTED"), User: &github.User{Login: github.String("bob")}, Body: github.String("Needs tests")},
+				{State: github.String("APPROVED"), User: &github.User{Login: github.String("bob")}},
+			},
+		},
+		Labels:    []*github.Label{{Name: github.String("docs")}, {Name: github.String("good first issue")}, {Name: github.String("help wanted")}},
+		Assignees: []string{"alice", "bob"},
+		Comments: map[int][]*github.IssueComment{
+			1: {{User: &github.User{Login: github.String("bob")}, Body: github.String("Confirmed on 2.1"), CreatedAt: created}},
+		},
+		Files: map[string]string{
+			"README.md":                "# Widgets\n",
+			"docs/guide.md":            "Read me first",
+			"docs/logo.png":            "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+			"docs/@types/widgets.d.ts": "export {}",
+			"testdata/huge.log":        strings.Repeat("x", 2<<20),
+		},
+		Refs: map[string]map[string]string{
+			"release/1.0": {"README.md": "# Widgets 1.0\n"},
+		},
+	})
+	t.Cleanup(fake.Close)
+
+	svc, err := tools.NewService(tools.Config{BaseURL: fake.URL, Now: func() time.Time { return now }})
+	require.NoError(t, err)
+	return newMCPServer(svc, opts), fake
+}
+
+// callTool sends a tools/call request through the MCP server, returning JSON-RPC errors as Go errors
+func callTool(t *testing.T, s *server.MCPServer, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	t.Helper()
+	msg, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	switch resp := s.HandleMessage(context.Background(), msg).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		require.True(t, ok, "unexpected result %T", resp.Result)
+		return result, nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(resp.Error.Message)
+	default:
+		t.Fatalf("unexpected response %T", resp)
+		return nil, nil
+	}
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.NotEmpty(t, result.Content)
+	return mcp.GetTextFromContent(result.Content[0])
+}
+
+func TestListIssuesTool(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	assert.Equal(t, "- #1: Crash on startup\n- #2: Docs typo\n", resultText(t, result))
+
+	structured, ok := result.StructuredContent.(issueListResult)
+	require.True(t, ok)
+	require.Len(t, structured.Issues, 2)
+	assert.Equal(t, 1, structured.Issues[0].Number)
+	assert.Equal(t, "open", structured.Issues[0].State)
+	assert.Empty(t, structured.NextCursor)
+
+	result, err = callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "widgets", "per_page": 1})
+	require.NoError(t, err)
+	assert.Contains(t, resultText(t, result), "- #1: Crash on startup\n\nMore results available. next_cursor: ")
+	assert.NotEmpty(t, result.StructuredContent.(issueListResult).NextCursor)
+}
+
+func TestListPRsTool(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "list_prs", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	assert.Equal(t, "- #3: Add feature\n- #4: WIP\n", resultText(t, result))
+
+	structured, ok := result.StructuredContent.(pullRequestListResult)
+	require.True(t, ok)
+	require.Len(t, structured.PullRequests, 2)
+	assert.True(t, structured.PullRequests[1].Draft)
+}
+
+func TestSearchIssuesTool(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "search_issues", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "query": "crash", "prioritize": true,
+	})
+	require.NoError(t, err)
+	text := resultText(t, result)
+	assert.Contains(t, text, "Found 1 issues related to 'crash'")
+	assert.Contains(t, text, "🔴 HIGH PRIORITY:\n- #1: Crash on startup (Score: 12 - 12 comments, 0 reactions)")
+
+	structured := result.StructuredContent.(searchIssuesResult)
+	require.Len(t, structured.Issues, 1)
+	assert.Equal(t, "high", structured.Issues[0].Priority)
+	assert.Equal(t, 12, *structured.Issues[0].Score)
+}
+
+func TestGetPendingReviewsTool(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "get_pending_reviews", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	assert.Equal(t, "Found 1 PRs pending review:\n\n- #4: WIP (opened 2025-05-29)\n  ⚠️  DRAFT PR\n", resultText(t, result))
+}
+
+func TestCreateIssueTool(t *testing.T) {
+	s, fake := newTestServer(t)
+
+	result, err := callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "title": "Flaky test", "labels": "bug, ci",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resultText(t, result), "✅ Issue created successfully!")
+	assert.Contains(t, resultText(t, result), "- URL: https://github.com/acme/widgets/issues/5")
+	assert.Equal(t, []string{"bug", "ci"}, result.StructuredContent.(createIssueResult).Issue.Labels)
+
+	repo := fake.Repo("acme", "widgets")
+	created := repo.Issues[len(repo.Issues)-1]
+	assert.Equal(t, "Flaky test", created.GetTitle())
+	require.Len(t, created.Labels, 2)
+	assert.Equal(t, "ci", created.Labels[1].GetName())
+}
+
+func TestCreateIssueDryRun(t *testing.T) {
+	s, fake := newTestServer(t)
+
+	result, err := callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "title": "Flaky test", "labels": "bug, ci", "dry_run": true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resultText(t, result), "📝 Dry run: no issue was created.")
+	assert.Contains(t, resultText(t, result), `"title": "Flaky test"`)
+	assert.Contains(t, resultText(t, result), `label "bug" does not exist in acme/widgets`)
+
+	structured := result.StructuredContent.(createIssueResult)
+	assert.True(t, structured.DryRun)
+	assert.Nil(t, structured.Issue)
+	assert.Equal(t, []string{"bug", "ci"}, structured.Request.GetLabels())
+	assert.Len(t, fake.Repo("acme", "widgets").Issues, 2)
+}
+
+func TestCreateIssueDryRunErrorKeepsItsCause(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "missing", "title": "Flaky test", "labels": "bug", "dry_run": true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeNotFound, errorCode(result))
+	assert.NotContains(t, resultText(t, result), "invalid issue")
+	assert.Contains(t, resultText(t, result), "Hint: repository not found or private")
+}
+
+func TestCreateIssueDryRunServerDefault(t *testing.T) {
+	s, fake := newTestServerWith(t, serverOptions{DryRun: true})
+
+	result, err := callTool(t, s, "create_issue", map[string]interface{}{"owner": "acme", "repo": "widgets", "title": "Flaky test"})
+	require.NoError(t, err)
+	assert.True(t, result.StructuredContent.(createIssueResult).DryRun)
+	assert.Len(t, fake.Repo("acme", "widgets").Issues, 2)
+
+	result, err = callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "title": "Flaky test", "dry_run": false,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resultText(t, result), "✅ Issue created successfully!")
+	assert.Len(t, fake.Repo("acme", "widgets").Issues, 3)
+}
+
+func TestAnalyzeIssuePriorityTool(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "analyze_issue_priority", map[string]interface{}{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	text := resultText(t, result)
+	assert.Contains(t, text, "🔴 CRITICAL (1 issues):\n- #1: Crash on startup (Score: 29)")
+	assert.Contains(t, text, "⚪ LOW (1 issues):\n- #2: Docs typo (Score: 0)")
+
+	structured := result.StructuredContent.(priorityAnalysisResult)
+	require.Len(t, structured.Issues, 2)
+	assert.Equal(t, "critical", structured.Issues[0].Priority)
+	assert.Equal(t, 29, *structured.Issues[0].Score)
+	assert.Equal(t, "low", structured.Issues[1].Priority)
+}
+
+func TestToolErrorForUnknownRepo(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "missing"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeNotFound, errorCode(result))
+	assert.Contains(t, resultText(t, result), "GitHub returned 404 Not Found")
+	assert.Contains(t, resultText(t, result), "Hint: repository not found or private")
+}
+
+func TestCreateIssueErrorIsToolError(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := callTool(t, s, "create_issue", map[string]interface{}{"owner": "acme", "repo": "widgets", "title": " "})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeInvalidArguments, errorCode(result))
+	assert.Contains(t, resultText(t, result), "❌ invalid arguments: title must not be empty")
+}
+
+func TestToolArgumentsValidatedAgainstSchema(t *testing.T) {
+	s, fake := newTestServer(t)
+
+	tool := s.GetTool("list_issues").Tool
+	assert.Equal(t, []string{"owner", "repo"}, tool.InputSchema.Required)
+	assert.Equal(t, false, tool.InputSchema.AdditionalProperties)
+	state := tool.InputSchema.Properties["state"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"open", "closed", "all"}, state["enum"])
+
+	result, err := callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "widgets", "state": "opne", "labels": "bug"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeInvalidArguments, errorCode(result))
+	assert.Contains(t, resultText(t, result), `unknown argument "labels"`)
+	assert.Contains(t, resultText(t, result), `state must be one of open, closed, all, got "opne"`)
+	assert.Empty(t, fake.Requests)
+}
+
+func TestToolArgumentsCoerced(t *testing.T) {
+	s, fake := newTestServer(t)
+
+	result, err := callTool(t, s, "list_issues", map[string]interface{}{"owner": "acme", "repo": "widgets", "per_page": "1", "max_results": "1"})
+	require.NoError(t, err)
+	require.False(t, result.IsError, resultText(t, result))
+	assert.Len(t, result.StructuredContent.(issueListResult).Issues, 1)
+
+	result, err = callTool(t, s, "create_issue", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "title": "Flaky", "labels": "bug, ci", "dry_run": "false",
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, resultText(t, result))
+	created := fake.Repo("acme", "widgets").Issues
+	require.Len(t, created, 3)
+	assert.Len(t, created[2].Labels, 2)
+}
+
+func toolNames(s *server.MCPServer) []string {
+	var names []string
+	for name := range s.ListTools() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReadOnlyModeHidesWriteTools(t *testing.T) {
+	s, _ := newTestServerWith(t, serverOptions{ReadOnly: true})
+	assert.Equal(t, []string{"analyze_issue_priority", "get_pending_reviews", "list_issues", "list_prs", "search_issues"}, toolNames(s))
+
+	// Naming the writes toolset explicitly does not override read-only
+	s, _ = newTestServerWith(t, serverOptions{ReadOnly: true, Toolsets: []string{"writes", "issues"}})
+	assert.Equal(t, []string{"list_issues"}, toolNames(s))
+}
+
+func TestToolsetsSelectTools(t *testing.T) {
+	s, _ := newTestServerWith(t, serverOptions{Toolsets: parseToolsets("pulls, writes")})
+	assert.Equal(t, []string{"create_issue", "get_pending_reviews", "list_prs"}, toolNames(s))
+
+	s, _ = newTestServerWith(t, serverOptions{Toolsets: parseToolsets("all")})
+	assert.Len(t, toolNames(s), 6)
+
+	assert.Error(t, serverOptions{Toolsets: parseToolsets("issues,bogus")}.validate())
+}
+
+func TestCreateIssueRefusedWhenReadOnly(t *testing.T) {
+	_, fake := newTestServer(t)
+	svc, err := tools.NewService(tools.Config{BaseURL: fake.URL})
+	require.NoError(t, err)
+	h := &handlers{github: svc, readOnly: true}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"owner": "acme", "repo": "widgets", "title": "Nope"}
+	result, err := h.createIssueHandler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, errCodeReadOnly, errorCode(result))
+	assert.Contains(t, resultText(t, result), "read-only mode")
+	assert.Len(t, fake.Repo("acme", "widgets").Issues, 2)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// progressMiddleware sends the progress of tool calls that page through
+// results or fan out over many requests as notifications/progress, for calls
+// whose caller supplied a progress token
+func progressMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		srv := server.ServerFromContext(ctx)
+		if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil || srv == nil {
+			return next(ctx, req)
+		}
+
+		token := req.Params.Meta.ProgressToken
+		return next(tools.WithProgress(ctx, func(done, total int, message string) {
+			params := map[string]any{"progressToken": token, "progress": done, "message": message}
+			if total > 0 {
+				params["total"] = total
+			}
+			// Progress is advisory: a client that went away still gets its result or error
+			_ = srv.SendNotificationToClient(ctx, "notifications/progress", params)
+		}), req)
+	}
+}
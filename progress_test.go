@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSession is a client session that keeps the notifications sent to it
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return "test" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+// callToolWithProgress calls a tool in a session, optionally with a progress
+// token, and returns the progress notifications the call sent
+func callToolWithProgress(t *testing.T, s *server.MCPServer, name string, args map[string]interface{}, token interface{}) []map[string]any {
+	t.Helper()
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 100)}
+	require.NoError(t, s.RegisterSession(context.Background(), session))
+	defer s.UnregisterSession(context.Background(), session.SessionID())
+
+	params := map[string]interface{}{"name": name, "arguments": args}
+	if token != nil {
+		params["_meta"] = map[string]interface{}{"progressToken": token}
+	}
+	msg, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": params})
+	require.NoError(t, err)
+
+	resp, ok := s.HandleMessage(s.WithContext(context.Background(), session), msg).(mcp.JSONRPCResponse)
+	require.True(t, ok, "tools/call failed")
+	require.False(t, resp.Result.(*mcp.CallToolResult).IsError)
+
+	var progress []map[string]any
+	for {
+		select {
+		case n := <-session.notifications:
+			if n.Method == "notifications/progress" {
+				progress = append(progress, n.Params.AdditionalFields)
+			}
+		default:
+			return progress
+		}
+	}
+}
+
+func TestPendingReviewsSendsProgress(t *testing.T) {
+	s, _ := newTestServer(t)
+	args := map[string]interface{}{"owner": "acme", "repo": "widgets"}
+
+	progress := callToolWithProgress(t, s, "get_pending_reviews", args, "reviews-1")
+	assert.Equal(t, []map[string]any{
+		{"progressToken": "reviews-1", "progress": 0, "total": 2, "message": "fetched 2 open PRs, checking their reviews"},
+		{"progressToken": "reviews-1", "progress": 1, "total": 2, "message": "checked 1/2 PRs"},
+		{"progressToken": "reviews-1", "progress": 2, "total": 2, "message": "checked 2/2 PRs"},
+	}, progress)
+
+	// Without a progress token nothing is sent
+	assert.Empty(t, callToolWithProgress(t, s, "get_pending_reviews", args, nil))
+}
+
+func TestListingSendsProgress(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	progress := callToolWithProgress(t, s, "list_prs", map[string]interface{}{
+		"owner": "acme", "repo": "widgets", "per_page": 1, "max_results": 5,
+	}, 7)
+	require.Len(t, progress, 1)
+	assert.Equal(t, "fetched 1/5 PRs", progress[0]["message"])
+	assert.Equal(t, 1, progress[0]["progress"])
+}
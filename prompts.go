@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// Prompts spell out the tool calls and resource reads of common maintainer
+// workflows, so agents don't need the same instructions pasted every time.
+// A prompt only mentions tools and resources that are enabled on the server.
+
+// workflowPrompts returns the prompts offered by the server with their toolsets
+func workflowPrompts(svc *tools.Service, opts serverOptions) []toolsetPrompt {
+	repoArgs := []mcp.PromptOption{
+		mcp.WithArgument("owner", mcp.ArgumentDescription("GitHub org or user"), mcp.RequiredArgument()),
+		mcp.WithArgument("repo", mcp.ArgumentDescription("GitHub repository name"), mcp.RequiredArgument()),
+	}
+	withRepo := func(opts ...mcp.PromptOption) []mcp.PromptOption {
+		return append(append([]mcp.PromptOption{}, repoArgs...), opts...)
+	}
+
+	return []toolsetPrompt{
+		{
+			toolsets: []string{toolsetAnalytics, toolsetIssues},
+			prompt: mcp.NewPrompt("triage_repo", withRepo(
+				mcp.WithPromptDescription("Triage the open issues of a repository: rank them by priority, "+
+					"read the most urgent in full and propose labels, owners and next steps"),
+				mcp.WithArgument("limit", mcp.ArgumentDescription("Maximum number of issues to analyze. Defaults to 20")),
+			)...),
+			handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return triagePrompt(ctx, svc, opts, req.Params.Arguments)
+			},
+		},
+		{
+			toolsets: []string{toolsetPulls},
+			prompt: mcp.NewPrompt("review_pr", withRepo(
+				mcp.WithPromptDescription("Review a pull request, with the PR, its earlier reviews and its conversation attached"),
+				mcp.WithArgument("number", mcp.ArgumentDescription("Pull request number"), mcp.RequiredArgument()),
+			)...),
+			handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return reviewPrompt(ctx, svc, opts, req.Params.Arguments)
+			},
+		},
+		{
+			toolsets: []string{toolsetPulls, toolsetIssues},
+			prompt: mcp.NewPrompt("weekly_standup", withRepo(
+				mcp.WithPromptDescription("Summarize what shipped, what is in progress, what waits on review "+
+					"and what is at risk in a repository"),
+				mcp.WithArgument("days", mcp.ArgumentDescription("Number of days to cover. Defaults to 7")),
+			)...),
+			handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return standupPrompt(svc, opts, req.Params.Arguments)
+			},
+		},
+		{
+			toolsets: []string{toolsetPulls},
+			prompt: mcp.NewPrompt("draft_release_notes", withRepo(
+				mcp.WithPromptDescription("Draft release notes from the pull requests merged since the last release"),
+				mcp.WithArgument("since", mcp.ArgumentDescription("Date of the previous release as YYYY-MM-DD. Defaults to 30 days ago")),
+				mcp.WithArgument("version", mcp.ArgumentDescription("Version being released, used as the heading")),
+			)...),
+			handler: func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return releaseNotesPrompt(svc, opts, req.Params.Arguments)
+			},
+		},
+	}
+}
+
+func triagePrompt(ctx context.Context, svc *tools.Service, opts serverOptions, args map[string]string) (*mcp.GetPromptResult, error) {
+	owner, repo, err := promptRepo(args)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := promptInt(args, "limit", 20)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 100 {
+		return nil, fmt.Errorf("limit must be at most 100, got %d", limit)
+	}
+
+	var messages []mcp.PromptMessage
+	// The README is context, not the subject: a repository without one is triaged all the same
+	if opts.enabled(toolsetContents) {
+		if readme, err := svc.GetReadme(ctx, owner, repo, ""); err == nil {
+			if contents, err := fileContents(fmt.Sprintf("github://%s/%s/readme", owner, repo), readme); err == nil {
+				messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(contents[0])))
+			}
+		}
+	}
+
+	var steps promptSteps
+	steps.add("Call analyze_issue_priority with owner %q, repo %q and limit %d to rank the open issues by comments, reactions, labels and age.", owner, repo, limit)
+	steps.add("Read each critical and high priority issue in full from github://%s/%s/issues/{number} to see the discussion so far.", owner, repo)
+	if opts.enabled(toolsetSearch) {
+		steps.add("Use search_issues to look for duplicates of those issues, including closed ones.")
+	}
+	steps.add("For every issue you read, propose labels, an owner if the discussion suggests one, and the next concrete step.")
+	steps.add("Finish with a table of issue, priority, proposed labels and next step, most urgent first.")
+
+	text := fmt.Sprintf("Triage the open issues of %s/%s.", owner, repo)
+	if len(messages) > 0 {
+		text += " The README is attached for context on the project."
+	}
+	text += "\n\n" + steps.String() + "\nDo not create or change any issue; this is a report for the maintainers."
+	messages = append(messages, mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)))
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Triage %s/%s", owner, repo), messages), nil
+}
+
+func reviewPrompt(ctx context.Context, svc *tools.Service, opts serverOptions, args map[string]string) (*mcp.GetPromptResult, error) {
+	owner, repo, err := promptRepo(args)
+	if err != nil {
+		return nil, err
+	}
+	number, err := promptInt(args, "number", 0)
+	if err != nil {
+		return nil, err
+	}
+	if number == 0 {
+		return nil, fmt.Errorf("missing required argument %q", "number")
+	}
+
+	thread, err := svc.GetPullRequestThread(ctx, owner, repo, number)
+	if err != nil {
+		return nil, resourceError(err)
+	}
+	pr := thread.PullRequest
+	uri := fmt.Sprintf("github://%s/%s/pulls/%d", owner, repo, number)
+
+	var steps promptSteps
+	steps.add("Summarize what the change does and why, from the description and the discussion.")
+	if opts.enabled(toolsetContents) {
+		// Forks carry the head branch in their own repository
+		headOwner, headRepo := owner, repo
+		if head := pr.GetHead().GetRepo(); head != nil {
+			headOwner, headRepo = head.GetOwner().GetLogin(), head.GetName()
+		}
+		steps.add("Read the files the change touches from github://%s/%s/contents/{path}@%s, and their current version from github://%s/%s/contents/{path}@%s.",
+			headOwner, headRepo, pr.GetHead().GetRef(), owner, repo, pr.GetBase().GetRef())
+	}
+	if len(thread.Reviews) > 0 {
+		steps.add("Check whether the feedback in the earlier reviews has been addressed.")
+	}
+	steps.add("Look for bugs, missing tests, unclear naming and anything that breaks compatibility.")
+	steps.add("End with a verdict (approve, request changes or comment) and your findings by file, most important first.")
+
+	text := fmt.Sprintf("Review pull request #%d in %s/%s, %q. The pull request, its reviews and its conversation are attached.\n\n%s\n"+
+		"Do not post the review; show it to me first.", number, owner, repo, pr.GetTitle(), steps.String())
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Review %s/%s#%d", owner, repo, number), []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(markdownContents(uri, renderPullRequest(owner, repo, thread))[0])),
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+func standupPrompt(svc *tools.Service, opts serverOptions, args map[string]string) (*mcp.GetPromptResult, error) {
+	owner, repo, err := promptRepo(args)
+	if err != nil {
+		return nil, err
+	}
+	days, err := promptInt(args, "days", 7)
+	if err != nil {
+		return nil, err
+	}
+	since := svc.Now().AddDate(0, 0, -days).Format("2006-01-02")
+
+	var steps promptSteps
+	steps.add("Call list_prs with owner %q, repo %q and state \"all\", and keep the pull requests created or updated since %s.", owner, repo, since)
+	steps.add("Call get_pending_reviews to find the pull requests waiting on a reviewer.")
+	steps.add("Call list_issues with state \"all\" and keep the issues opened or closed since %s.", since)
+	if opts.enabled(toolsetAnalytics) {
+		steps.add("Call analyze_issue_priority to spot urgent issues nobody is working on.")
+	}
+	steps.add("Read github://%s/%s/pulls/{number} or github://%s/%s/issues/{number} when a title is not enough to say what happened.", owner, repo, owner, repo)
+
+	text := fmt.Sprintf("Prepare a standup update for %s/%s covering the last %d days, since %s.\n\n%s\n"+
+		"Write it as four short sections: Shipped, In progress, Waiting on review and Risks. "+
+		"Give one line per item with its number and author.", owner, repo, days, since, steps.String())
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Standup for %s/%s", owner, repo), []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+func releaseNotesPrompt(svc *tools.Service, opts serverOptions, args map[string]string) (*mcp.GetPromptResult, error) {
+	owner, repo, err := promptRepo(args)
+	if err != nil {
+		return nil, err
+	}
+	since := svc.Now().AddDate(0, 0, -30).Format("2006-01-02")
+	if s := strings.TrimSpace(args["since"]); s != "" {
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, fmt.Errorf("since must be a date as YYYY-MM-DD, got %q", s)
+		}
+		since = s
+	}
+	release := "the next release"
+	if version := strings.TrimSpace(args["version"]); version != "" {
+		release = version
+	}
+
+	var steps promptSteps
+	steps.add("Call list_prs with owner %q, repo %q and state \"closed\", following the returned cursor until the pull requests are older than %s.", owner, repo, since)
+	steps.add("Read github://%s/%s/pulls/{number} for each of them, and keep only those merged after %s.", owner, repo, since)
+	steps.add("Group the changes into Breaking changes, Features, Fixes, Documentation and Maintenance, going by labels, titles and descriptions.")
+	steps.add("Write one line per change in the imperative mood, ending with the pull request number and @author.")
+	if opts.enabled(toolsetContents) {
+		steps.add("Match the format of the existing changelog at github://%s/%s/contents/CHANGELOG.md if the repository has one.", owner, repo)
+	}
+
+	text := fmt.Sprintf("Draft release notes for %s of %s/%s, covering the pull requests merged since %s.\n\n%s\n"+
+		"Reply with the markdown only, under a heading for %s.", release, owner, repo, since, steps.String(), release)
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Release notes for %s/%s", owner, repo), []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+// promptSteps numbers the instructions of a prompt
+type promptSteps []string
+
+func (s *promptSteps) add(format string, args ...interface{}) {
+	*s = append(*s, fmt.Sprintf(format, args...))
+}
+
+func (s promptSteps) String() string {
+	var b strings.Builder
+	for i, step := range s {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	return b.String()
+}
+
+// promptRepo returns the owner and repo arguments every prompt requires
+func promptRepo(args map[string]string) (owner, repo string, err error) {
+	owner, repo = strings.TrimSpace(args["owner"]), strings.TrimSpace(args["repo"])
+	if owner == "" {
+		return "", "", fmt.Errorf("missing required argument %q", "owner")
+	}
+	if repo == "" {
+		return "", "", fmt.Errorf("missing required argument %q", "repo")
+	}
+	return owner, repo, nil
+}
+
+// promptInt parses an optional positive integer argument
+func promptInt(args map[string]string, name string, def int) (int, error) {
+	s := strings.TrimSpace(args[name])
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", name, s)
+	}
+	return n, nil
+}
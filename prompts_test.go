@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// getPrompt sends a prompts/get request through the MCP server
+func getPrompt(t *testing.T, s *server.MCPServer, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	t.Helper()
+	msg, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "prompts/get",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	switch resp := s.HandleMessage(context.Background(), msg).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(mcp.GetPromptResult)
+		require.True(t, ok, "unexpected result %T", resp.Result)
+		return &result, nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(resp.Error.Message)
+	default:
+		t.Fatalf("unexpected response %T", resp)
+		return nil, nil
+	}
+}
+
+// promptText joins the text messages of a prompt
+func promptText(result *mcp.GetPromptResult) string {
+	var text string
+	for _, msg := range result.Messages {
+		if content, ok := msg.Content.(mcp.TextContent); ok {
+			text += content.Text
+		}
+	}
+	return text
+}
+
+func TestTriagePrompt(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := getPrompt(t, s, "triage_repo", map[string]string{"owner": "acme", "repo": "widgets", "limit": "10"})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 2)
+	readme := result.Messages[0].Content.(mcp.EmbeddedResource).Resource.(mcp.TextResourceContents)
+	assert.Equal(t, "github://acme/widgets/readme", readme.URI)
+	assert.Equal(t, "# Widgets\n", readme.Text)
+
+	text := promptText(result)
+	assert.Contains(t, text, `1. Call analyze_issue_priority with owner "acme", repo "widgets" and limit 10`)
+	assert.Contains(t, text, "github://acme/widgets/issues/{number}")
+	assert.Contains(t, text, "search_issues")
+}
+
+func TestReviewPrompt(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := getPrompt(t, s, "review_pr", map[string]string{"owner": "acme", "repo": "widgets", "number": "3"})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 2)
+	pr := result.Messages[0].Content.(mcp.EmbeddedResource).Resource.(mcp.TextResourceContents)
+	assert.Equal(t, "github://acme/widgets/pulls/3", pr.URI)
+	assert.Contains(t, pr.Text, "# Add feature (#3)")
+	assert.Contains(t, promptText(result), "Check whether the feedback in the earlier reviews has been addressed.")
+
+	_, err = getPrompt(t, s, "review_pr", map[string]string{"owner": "acme", "repo": "widgets", "number": "99"})
+	assert.ErrorContains(t, err, "not_found")
+}
+
+func TestStandupAndReleaseNotesPrompts(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	result, err := getPrompt(t, s, "weekly_standup", map[string]string{"owner": "acme", "repo": "widgets", "days": "14"})
+	require.NoError(t, err)
+	text := promptText(result)
+	assert.Contains(t, text, "covering the last 14 days, since 2025-05-18")
+	assert.Contains(t, text, "get_pending_reviews")
+	assert.Contains(t, text, "analyze_issue_priority")
+
+	result, err = getPrompt(t, s, "draft_release_notes", map[string]string{"owner": "acme", "repo": "widgets", "version": "v2.0.0"})
+	require.NoError(t, err)
+	text = promptText(result)
+	assert.Contains(t, text, "Draft release notes for v2.0.0 of acme/widgets, covering the pull requests merged since 2025-05-02")
+	assert.Contains(t, text, "github://acme/widgets/contents/CHANGELOG.md")
+}
+
+func TestPromptArgumentErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	_, err := getPrompt(t, s, "triage_repo", map[string]string{"owner": "acme"})
+	assert.ErrorContains(t, err, `missing required argument "repo"`)
+	_, err = getPrompt(t, s, "weekly_standup", map[string]string{"owner": "acme", "repo": "widgets", "days": "soon"})
+	assert.ErrorContains(t, err, `days must be a positive integer, got "soon"`)
+	_, err = getPrompt(t, s, "draft_release_notes", map[string]string{"owner": "acme", "repo": "widgets", "since": "last week"})
+	assert.ErrorContains(t, err, "since must be a date as YYYY-MM-DD")
+}
+
+func TestPromptsFollowToolsets(t *testing.T) {
+	s, _ := newTestServerWith(t, serverOptions{Toolsets: []string{toolsetPulls}})
+
+	_, err := getPrompt(t, s, "review_pr", map[string]string{"owner": "acme", "repo": "widgets", "number": "3"})
+	assert.NoError(t, err)
+	_, err = getPrompt(t, s, "triage_repo", map[string]string{"owner": "acme", "repo": "widgets"})
+	assert.Error(t, err)
+
+	// Without the contents toolset the release notes don't point at the changelog
+	result, err := getPrompt(t, s, "draft_release_notes", map[string]string{"owner": "acme", "repo": "widgets"})
+	require.NoError(t, err)
+	assert.NotContains(t, promptText(result), "CHANGELOG.md")
+}
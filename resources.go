@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// maxResourceBytes is the largest file served as a resource. It matches the
+// largest file the contents API returns inline.
+const maxResourceBytes = 1 << 20
+
+// mimeDirectory is the MIME type of directory entries in a listing
+const mimeDirectory = "inode/directory"
+
+// mimeTypes covers source and text formats the system MIME tables often miss
+var mimeTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".go":       "text/x-go",
+	".mod":      "text/plain",
+	".sum":      "text/plain",
+	".py":       "text/x-python",
+	".rs":       "text/x-rust",
+	".java":     "text/x-java",
+	".c":        "text/x-c",
+	".h":        "text/x-c",
+	".ts":       "text/x-typescript",
+	".sh":       "text/x-shellscript",
+	".yaml":     "application/yaml",
+	".yml":      "application/yaml",
+	".toml":     "application/toml",
+	".json":     "application/json",
+	".txt":      "text/plain",
+}
+
+// repoResourceTemplates returns the resource templates for reading repository content
+func repoResourceTemplates(svc *tools.Service) []server.ServerResourceTemplate {
+	return []server.ServerResourceTemplate{
+		{
+			Template: mcp.NewResourceTemplate("github://{owner}/{repo}/contents/{+path}", "Repository contents",
+				mcp.WithTemplateDescription("A file or directory in a GitHub repository. Append @ and a branch, tag or "+
+					"commit SHA to read another ref than the default branch, e.g. github://golang/go/contents/README.md@go1.22.0; "+
+					"write an @ in the path as %40. A directory is returned as a list of its entries' resources."),
+			),
+			Handler: contentsResourceHandler(svc),
+		},
+		{
+			Template: mcp.NewResourceTemplate("github://{owner}/{repo}/readme", "Repository README",
+				mcp.WithTemplateDescription("The README of a GitHub repository on its default branch"),
+			),
+			Handler: readmeResourceHandler(svc),
+		},
+	}
+}
+
+// contentsResourceHandler reads github://{owner}/{repo}/contents/{path}@{ref}
+func contentsResourceHandler(svc *tools.Service) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		owner, repo, filePath, ref, err := parseContentsURI(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		file, dir, err := svc.GetContents(ctx, owner, repo, filePath, ref)
+		if err != nil {
+			return nil, resourceError(err)
+		}
+		if file == nil {
+			return directoryContents(owner, repo, ref, dir), nil
+		}
+		return fileContents(req.Params.URI, file)
+	}
+}
+
+// readmeResourceHandler reads github://{owner}/{repo}/readme
+func readmeResourceHandler(svc *tools.Service) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		owner, repo, _, _, err := parseResourceURI(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+
+		readme, err := svc.GetReadme(ctx, owner, repo, "")
+		if err != nil {
+			return nil, resourceError(err)
+		}
+		return fileContents(req.Params.URI, readme)
+	}
+}
+
+// parseResourceURI splits a github://{owner}/{repo}/{kind}/{rest} URI. The
+// raw URI is parsed rather than the template's variables, which are already
+// unescaped, so that callers can tell %40 from a literal @.
+func parseResourceURI(uri string) (owner, repo, kind, rest string, err error) {
+	trimmed, ok := strings.CutPrefix(uri, "github://")
+	parts := strings.SplitN(trimmed, "/", 4)
+	if !ok || len(parts) < 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", "", fmt.Errorf("invalid resource URI %q: expected github://{owner}/{repo}/...", uri)
+	}
+	if len(parts) == 4 {
+		rest = parts[3]
+	}
+	return parts[0], parts[1], parts[2], rest, nil
+}
+
+// parseContentsURI splits a github://{owner}/{repo}/contents/{path}@{ref} URI.
+// An @ in the path is written as %40.
+func parseContentsURI(uri string) (owner, repo, filePath, ref string, err error) {
+	owner, repo, kind, rest, err := parseResourceURI(uri)
+	if err == nil && kind != "contents" {
+		err = fmt.Errorf("invalid contents URI %q: expected github://{owner}/{repo}/contents/{path}@{ref}", uri)
+	}
+	if err != nil {
+		return "", "", "", "", err
+	}
+
+	rawPath, rawRef, _ := strings.Cut(rest, "@")
+	if filePath, err = url.PathUnescape(rawPath); err == nil {
+		ref, err = url.PathUnescape(rawRef)
+	}
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("invalid contents URI %q: %w", uri, err)
+	}
+	return owner, repo, strings.Trim(filePath, "/"), ref, nil
+}
+
+// contentsURI is the resource URI of filePath in owner/repo at ref
+func contentsURI(owner, repo, filePath, ref string) string {
+	segments := strings.Split(filePath, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+	}
+	uri := fmt.Sprintf("github://%s/%s/contents/%s", owner, repo, strings.Join(segments, "/"))
+	if ref != "" {
+		uri += "@" + ref
+	}
+	return uri
+}
+
+// fileContents returns a file as text when it is valid UTF-8 and as a base64 blob otherwise
+func fileContents(uri string, file *github.RepositoryContent) ([]mcp.ResourceContents, error) {
+	switch file.GetType() {
+	case "file":
+	case "symlink":
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "text/plain", Text: file.GetTarget()}}, nil
+	default:
+		return nil, fmt.Errorf("%s is a %s, not a file", file.GetPath(), file.GetType())
+	}
+
+	if file.GetSize() > maxResourceBytes || file.GetEncoding() == "none" {
+		return nil, fmt.Errorf("%s is %d bytes, larger than the %d byte limit for resources", file.GetPath(), file.GetSize(), maxResourceBytes)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", file.GetPath(), err)
+	}
+	data := []byte(content)
+
+	mimeType := contentType(file.GetName(), data)
+	if isText(data) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: mimeType, Text: content}}, nil
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{URI: uri, MIMEType: mimeType, Blob: base64.StdEncoding.EncodeToString(data)}}, nil
+}
+
+// directoryContents lists a directory as one entry per file or subdirectory,
+// each with the URI to read it by
+func directoryContents(owner, repo, ref string, entries []*github.RepositoryContent) []mcp.ResourceContents {
+	contents := make([]mcp.ResourceContents, 0, len(entries))
+	for _, entry := range entries {
+		name, mimeType := entry.GetName(), contentType(entry.GetName(), nil)
+		if entry.GetType() == "dir" {
+			name, mimeType = name+"/", mimeDirectory
+		}
+		contents = append(contents, mcp.TextResourceContents{
+			URI:      contentsURI(owner, repo, entry.GetPath(), ref),
+			MIMEType: mimeType,
+			Text:     name,
+			Meta:     map[string]any{"type": entry.GetType(), "size": entry.GetSize()},
+		})
+	}
+	return contents
+}
+
+// contentType guesses the MIME type of a file from its name, then from its
+// content when there is any
+func contentType(name string, data []byte) string {
+	ext := strings.ToLower(path.Ext(name))
+	if mimeType, ok := mimeTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); mimeType != "" {
+		return mimeType
+	}
+	if data == nil {
+		return "application/octet-stream"
+	}
+	if isText(data) {
+		return "text/plain"
+	}
+	return http.DetectContentType(data)
+}
+
+// isText reports whether data can be returned as text
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readResource sends a resources/read request through the MCP server
+func readResource(t *testing.T, s *server.MCPServer, uri string) ([]mcp.ResourceContents, error) {
+	t.Helper()
+	msg, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]interface{}{"uri": uri},
+	})
+	require.NoError(t, err)
+
+	switch resp := s.HandleMessage(context.Background(), msg).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(mcp.ReadResourceResult)
+		require.True(t, ok, "unexpected result %T", resp.Result)
+		return result.Contents, nil
+	case mcp.JSONRPCError:
+		return nil, errors.New(resp.Error.Message)
+	default:
+		t.Fatalf("unexpected response %T", resp)
+		return nil, nil
+	}
+}
+
+func TestReadFileResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/contents/docs/guide.md")
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.ResourceContents{mcp.TextResourceContents{
+		URI: "github://acme/widgets/contents/docs/guide.md", MIMEType: "text/markdown", Text: "Read me first",
+	}}, contents)
+
+	contents, err = readResource(t, s, "github://acme/widgets/contents/README.md@release/1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "# Widgets 1.0\n", contents[0].(mcp.TextResourceContents).Text)
+
+	// %40 is an @ in the path, not the ref separator
+	contents, err = readResource(t, s, "github://acme/widgets/contents/docs/%40types/widgets.d.ts")
+	require.NoError(t, err)
+	assert.Equal(t, "export {}", contents[0].(mcp.TextResourceContents).Text)
+}
+
+func TestReadBinaryResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/contents/docs/logo.png")
+	require.NoError(t, err)
+	blob, ok := contents[0].(mcp.BlobResourceContents)
+	require.True(t, ok, "binary files are returned as blobs, got %T", contents[0])
+	assert.Equal(t, "image/png", blob.MIMEType)
+	data, err := base64.StdEncoding.DecodeString(blob.Blob)
+	require.NoError(t, err)
+	assert.Equal(t, "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", string(data))
+}
+
+func TestReadDirectoryResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/contents/docs@release/1.0")
+	assert.Error(t, err, "docs does not exist at release/1.0")
+
+	contents, err = readResource(t, s, "github://acme/widgets/contents/docs")
+	require.NoError(t, err)
+	var uris, texts, mimeTypes []string
+	for _, content := range contents {
+		entry := content.(mcp.TextResourceContents)
+		uris, texts, mimeTypes = append(uris, entry.URI), append(texts, entry.Text), append(mimeTypes, entry.MIMEType)
+	}
+	assert.Equal(t, []string{
+		"github://acme/widgets/contents/docs/%40types",
+		"github://acme/widgets/contents/docs/guide.md",
+		"github://acme/widgets/contents/docs/logo.png",
+	}, uris)
+	assert.Equal(t, []string{"@types/", "guide.md", "logo.png"}, texts)
+	assert.Equal(t, []string{mimeDirectory, "text/markdown", "image/png"}, mimeTypes)
+
+	// The repository root
+	contents, err = readResource(t, s, "github://acme/widgets/contents/")
+	require.NoError(t, err)
+	assert.Len(t, contents, 3)
+}
+
+func TestReadResourceErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	_, err := readResource(t, s, "github://acme/widgets/contents/testdata/huge.log")
+	assert.ErrorContains(t, err, "testdata/huge.log is 2097152 bytes, larger than the 1048576 byte limit for resources")
+
+	_, err = readResource(t, s, "github://acme/missing/contents/README.md")
+	assert.ErrorContains(t, err, "GitHub returned 404 Not Found")
+	assert.ErrorContains(t, err, "(not_found)")
+}
+
+func TestReadReadmeResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/readme")
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.ResourceContents{mcp.TextResourceContents{
+		URI: "github://acme/widgets/readme", MIMEType: "text/markdown", Text: "# Widgets\n",
+	}}, contents)
+}
+
+func TestContentsToolsetRegistersResources(t *testing.T) {
+	s, _ := newTestServerWith(t, serverOptions{Toolsets: []string{toolsetIssues}})
+	_, err := readResource(t, s, "github://acme/widgets/readme")
+	assert.Error(t, err)
+}
+
+func TestContentsURI(t *testing.T) {
+	assert.Equal(t, "github://acme/widgets/contents/a%20b/%40c.md@feature/x", contentsURI("acme", "widgets", "a b/@c.md", "feature/x"))
+
+	owner, repo, path, ref, err := parseContentsURI("github://acme/widgets/contents/a%20b/%40c.md@feature/x")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "widgets", "a b/@c.md", "feature/x"}, []string{owner, repo, path, ref})
+
+	_, _, _, _, err = parseContentsURI("github://acme/widgets/issues/1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// issueResult is the structured form of an issue returned by the issue tools
+type issueResult struct {
+	Number    int       `json:"number" jsonschema:"description=Issue number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state" jsonschema:"enum=open,enum=closed"`
+	Labels    []string  `json:"labels"`
+	URL       string    `json:"url" jsonschema:"description=Issue page on GitHub"`
+	Author    string    `json:"author" jsonschema:"description=Login of the user who opened the issue"`
+	Assignees []string  `json:"assignees,omitempty"`
+	Comments  int       `json:"comments"`
+	Reactions int       `json:"reactions"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Score and Priority are set by the tools that rank issues
+	Score    *int   `json:"score,omitempty" jsonschema:"description=Priority score; higher is more urgent"`
+	Priority string `json:"priority,omitempty" jsonschema:"enum=critical,enum=high,enum=medium,enum=low"`
+}
+
+// pullRequestResult is the structured form of a pull request returned by the PR tools
+type pullRequestResult struct {
+	Number    int       `json:"number" jsonschema:"description=Pull request number"`
+	Title     string    `json:"title"`
+	State     string    `json:"state" jsonschema:"enum=open,enum=closed"`
+	Draft     bool      `json:"draft"`
+	Labels    []string  `json:"labels"`
+	URL       string    `json:"url" jsonschema:"description=Pull request page on GitHub"`
+	Author    string    `json:"author" jsonschema:"description=Login of the user who opened the pull request"`
+	Head      string    `json:"head,omitempty" jsonschema:"description=Branch the changes come from"`
+	Base      string    `json:"base,omitempty" jsonschema:"description=Branch the changes merge into"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// issueListResult is the output of list_issues
+type issueListResult struct {
+	Issues     []issueResult `json:"issues"`
+	NextCursor string        `json:"next_cursor,omitempty" jsonschema:"description=Pass as cursor to fetch the next page"`
+}
+
+// pullRequestListResult is the output of list_prs and get_pending_reviews
+type pullRequestListResult struct {
+	PullRequests []pullRequestResult `json:"pull_requests"`
+	NextCursor   string              `json:"next_cursor,omitempty" jsonschema:"description=Pass as cursor to fetch the next page"`
+}
+
+// searchIssuesResult is the output of search_issues
+type searchIssuesResult struct {
+	Query      string        `json:"query"`
+	Issues     []issueResult `json:"issues"`
+	NextCursor string        `json:"next_cursor,omitempty" jsonschema:"description=Pass as cursor to fetch the next page"`
+}
+
+// createIssueResult is the output of create_issue: the created issue, or
+// for a dry run the request that would have been sent
+type createIssueResult struct {
+	DryRun   bool                 `json:"dry_run" jsonschema:"description=True when nothing was created"`
+	Issue    *issueResult         `json:"issue,omitempty"`
+	Request  *github.IssueRequest `json:"request,omitempty" jsonschema:"description=Body of the POST /repos/{owner}/{repo}/issues request a real call would send"`
+	Warnings []string             `json:"warnings,omitempty" jsonschema:"description=Labels or assignees that did not resolve against the repository"`
+}
+
+// auditLogResult is the output of get_audit_log, newest first
+type auditLogResult struct {
+	Entries []auditEntry `json:"entries"`
+}
+
+// priorityAnalysisResult is the output of analyze_issue_priority, most urgent first
+type priorityAnalysisResult struct {
+	Issues []issueResult `json:"issues"`
+}
+
+func newIssueResult(issue *github.Issue) issueResult {
+	r := issueResult{
+		Number:    issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		State:     issue.GetState(),
+		Labels:    labelNames(issue.Labels),
+		URL:       issue.GetHTMLURL(),
+		Author:    issue.GetUser().GetLogin(),
+		Comments:  issue.GetComments(),
+		Reactions: issue.GetReactions().GetTotalCount(),
+		CreatedAt: issue.GetCreatedAt().Time,
+		UpdatedAt: issue.GetUpdatedAt().Time,
+	}
+	for _, user := range issue.Assignees {
+		r.Assignees = append(r.Assignees, user.GetLogin())
+	}
+	if len(r.Assignees) == 0 && issue.Assignee != nil {
+		r.Assignees = []string{issue.GetAssignee().GetLogin()}
+	}
+	return r
+}
+
+func newIssueResults(issues []*github.Issue) []issueResult {
+	out := make([]issueResult, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, newIssueResult(issue))
+	}
+	return out
+}
+
+func newPullRequestResults(prs []*github.PullRequest) []pullRequestResult {
+	out := make([]pullRequestResult, 0, len(prs))
+	for _, pr := range prs {
+		out = append(out, pullRequestResult{
+			Number:    pr.GetNumber(),
+			Title:     pr.GetTitle(),
+			State:     pr.GetState(),
+			Draft:     pr.GetDraft(),
+			Labels:    labelNames(pr.Labels),
+			URL:       pr.GetHTMLURL(),
+			Author:    pr.GetUser().GetLogin(),
+			Head:      pr.GetHead().GetRef(),
+			Base:      pr.GetBase().GetRef(),
+			CreatedAt: pr.GetCreatedAt().Time,
+			UpdatedAt: pr.GetUpdatedAt().Time,
+		})
+	}
+	return out
+}
+
+func labelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
@@ -1,16 +1,15 @@
-body='{
-  "jsonrpc": "2.0",
-  "id": 1,
-  "method": "call_tool",
-  "params": {
-    "tool": "list_open_prs",
-    "arguments": {
-      "owner": "kubernetes",
-      "repo": "kubernetes"
-    }
-  }
-}'
+# Sends input.json to the server over stdio. MCP stdio messages are
+# newline-delimited JSON-RPC, and a session starts with initialize.
+# For a quicker check without the protocol, use:
+#   go run . call list_prs --arg owner=kubernetes --arg repo=kubernetes
 
-content_length=$(echo -n "$body" | wc -c)
+init='{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test.sh","version":"0.1.0"}}}'
+initialized='{"jsonrpc":"2.0","method":"notifications/initialized"}'
 
-printf "Content-Length: %d\r\n\r\n%s" "$content_length" "$body" | go run main.go
\ No newline at end of file
+{
+  echo "$init"
+  echo "$initialized"
+  tr -d '\n' < input.json
+  echo
+  sleep 5
+} | go run . serve
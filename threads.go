@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// issueResourceTemplate renders an issue with its comments as markdown
+func issueResourceTemplate(svc *tools.Service) server.ServerResourceTemplate {
+	return server.ServerResourceTemplate{
+		Template: mcp.NewResourceTemplate("github://{owner}/{repo}/issues/{number}", "Issue",
+			mcp.WithTemplateDescription("A GitHub issue as markdown: its body, labels, assignees and every comment. "+
+				"Pin an issue found with list_issues or search_issues to keep it in context."),
+			mcp.WithTemplateMIMEType("text/markdown"),
+		),
+		Handler: func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			owner, repo, number, err := parseThreadURI(req.Params.URI, "issues")
+			if err != nil {
+				return nil, err
+			}
+
+			thread, err := svc.GetIssueThread(ctx, owner, repo, number)
+			if err != nil {
+				return nil, resourceError(err)
+			}
+			return markdownContents(req.Params.URI, renderIssue(owner, repo, thread)), nil
+		},
+	}
+}
+
+// pullResourceTemplate renders a pull request with its reviews and comments as markdown
+func pullResourceTemplate(svc *tools.Service) server.ServerResourceTemplate {
+	return server.ServerResourceTemplate{
+		Template: mcp.NewResourceTemplate("github://{owner}/{repo}/pulls/{number}", "Pull request",
+			mcp.WithTemplateDescription("A GitHub pull request as markdown: its body, branches, labels, assignees, "+
+				"review state, reviews and every comment. Pin a PR found with list_prs to keep it in context."),
+			mcp.WithTemplateMIMEType("text/markdown"),
+		),
+		Handler: func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			owner, repo, number, err := parseThreadURI(req.Params.URI, "pulls")
+			if err != nil {
+				return nil, err
+			}
+
+			thread, err := svc.GetPullRequestThread(ctx, owner, repo, number)
+			if err != nil {
+				return nil, resourceError(err)
+			}
+			return markdownContents(req.Params.URI, renderPullRequest(owner, repo, thread)), nil
+		},
+	}
+}
+
+// parseThreadURI splits a github://{owner}/{repo}/{kind}/{number} URI
+func parseThreadURI(uri, kind string) (owner, repo string, number int, err error) {
+	owner, repo, gotKind, rest, err := parseResourceURI(uri)
+	if err != nil {
+		return "", "", 0, err
+	}
+	number, convErr := strconv.Atoi(rest)
+	if gotKind != kind || convErr != nil || number < 1 {
+		return "", "", 0, fmt.Errorf("invalid resource URI %q: expected github://{owner}/{repo}/%s/{number}", uri, kind)
+	}
+	return owner, repo, number, nil
+}
+
+func markdownContents(uri, text string) []mcp.ResourceContents {
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "text/markdown", Text: text}}
+}
+
+// renderIssue formats an issue and its comments as a markdown document
+func renderIssue(owner, repo string, thread *tools.IssueThread) string {
+	issue := thread.Issue
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (#%d)\n\n", issue.GetTitle(), issue.GetNumber())
+	fmt.Fprintf(&b, "- **Repository:** %s/%s\n", owner, repo)
+	fmt.Fprintf(&b, "- **State:** %s\n", issue.GetState())
+	writeField(&b, "Author", mention(issue.GetUser()))
+	writeField(&b, "Opened", formatTime(issue.CreatedAt))
+	writeField(&b, "Updated", formatTime(issue.UpdatedAt))
+	writeField(&b, "Closed", formatTime(issue.ClosedAt))
+	fmt.Fprintf(&b, "- **Labels:** %s\n", labelList(issue.Labels))
+	fmt.Fprintf(&b, "- **Assignees:** %s\n", userList(issue.Assignees, issue.Assignee))
+	writeField(&b, "Milestone", issue.GetMilestone().GetTitle())
+	writeField(&b, "URL", issue.GetHTMLURL())
+	if issue.IsPullRequest() {
+		fmt.Fprintf(&b, "\nThis issue is a pull request; read github://%s/%s/pulls/%d for its branches and reviews.\n",
+			owner, repo, issue.GetNumber())
+	}
+
+	writeBody(&b, issue.GetBody())
+	writeComments(&b, thread.Comments)
+	return b.String()
+}
+
+// renderPullRequest formats a pull request, its reviews and its comments as a markdown document
+func renderPullRequest(owner, repo string, thread *tools.PullRequestThread) string {
+	pr := thread.PullRequest
+	verdicts, state := reviewState(thread.Reviews)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (#%d)\n\n", pr.GetTitle(), pr.GetNumber())
+	fmt.Fprintf(&b, "- **Repository:** %s/%s\n", owner, repo)
+	status := pr.GetState()
+	if pr.GetMerged() || pr.MergedAt != nil {
+		status = "merged"
+	}
+	if pr.GetDraft() {
+		status += " (draft)"
+	}
+	fmt.Fprintf(&b, "- **State:** %s\n", status)
+	writeField(&b, "Author", mention(pr.GetUser()))
+	if pr.Head != nil && pr.Base != nil {
+		fmt.Fprintf(&b, "- **Branches:** `%s` → `%s`\n", pr.GetHead().GetLabel(), pr.GetBase().GetRef())
+	}
+	writeField(&b, "Opened", formatTime(pr.CreatedAt))
+	writeField(&b, "Updated", formatTime(pr.UpdatedAt))
+	writeField(&b, "Merged", formatTime(pr.MergedAt))
+	if pr.MergedAt == nil {
+		writeField(&b, "Closed", formatTime(pr.ClosedAt))
+	}
+	fmt.Fprintf(&b, "- **Labels:** %s\n", labelList(pr.Labels))
+	fmt.Fprintf(&b, "- **Assignees:** %s\n", userList(pr.Assignees, pr.Assignee))
+	if len(pr.RequestedReviewers) > 0 {
+		fmt.Fprintf(&b, "- **Requested reviewers:** %s\n", userList(pr.RequestedReviewers, nil))
+	}
+	fmt.Fprintf(&b, "- **Review state:** %s\n", state)
+	for _, verdict := range verdicts {
+		fmt.Fprintf(&b, "  - %s\n", verdict)
+	}
+	if pr.Additions != nil || pr.Deletions != nil || pr.ChangedFiles != nil {
+		fmt.Fprintf(&b, "- **Changes:** +%d −%d in %d files\n", pr.GetAdditions(), pr.GetDeletions(), pr.GetChangedFiles())
+	}
+	writeField(&b, "URL", pr.GetHTMLURL())
+
+	writeBody(&b, pr.GetBody())
+
+	var reviews []*github.PullRequestReview
+	for _, review := range thread.Reviews {
+		if review.GetState() != "PENDING" {
+			reviews = append(reviews, review)
+		}
+	}
+	if len(reviews) > 0 {
+		fmt.Fprintf(&b, "\n## Reviews (%d)\n", len(reviews))
+		for _, review := range reviews {
+			fmt.Fprintf(&b, "\n### %s %s", mention(review.GetUser()), reviewVerb(review.GetState()))
+			if when := formatTime(review.SubmittedAt); when != "" {
+				fmt.Fprintf(&b, " on %s", when)
+			}
+			b.WriteString("\n")
+			if body := strings.TrimSpace(review.GetBody()); body != "" {
+				fmt.Fprintf(&b, "\n%s\n", body)
+			}
+		}
+	}
+
+	writeComments(&b, thread.Comments)
+	return b.String()
+}
+
+// reviewState summarizes reviews from each reviewer's latest verdict. Plain
+// comments leave a verdict standing and a dismissal withdraws it. Any
+// outstanding change request outweighs approvals.
+func reviewState(reviews []*github.PullRequestReview) ([]string, string) {
+	var reviewers []string
+	latest := make(map[string]string)
+	for _, review := range reviews {
+		login := mention(review.GetUser())
+		switch review.GetState() {
+		case "APPROVED", "CHANGES_REQUESTED":
+			if _, seen := latest[login]; !seen {
+				reviewers = append(reviewers, login)
+			}
+			latest[login] = review.GetState()
+		case "DISMISSED":
+			if _, seen := latest[login]; seen {
+				latest[login] = ""
+			}
+		}
+	}
+
+	var verdicts []string
+	approved, changesRequested := 0, 0
+	for _, login := range reviewers {
+		switch latest[login] {
+		case "APPROVED":
+			approved++
+		case "CHANGES_REQUESTED":
+			changesRequested++
+		default:
+			continue
+		}
+		verdicts = append(verdicts, fmt.Sprintf("%s %s", login, reviewVerb(latest[login])))
+	}
+
+	switch {
+	case changesRequested > 0:
+		return verdicts, "changes requested"
+	case approved > 0:
+		return verdicts, "approved"
+	case len(reviews) > 0:
+		return verdicts, "reviewed, not yet approved"
+	default:
+		return verdicts, "no reviews yet"
+	}
+}
+
+// reviewVerb describes a review state as a verb phrase
+func reviewVerb(state string) string {
+	switch state {
+	case "APPROVED":
+		return "approved"
+	case "CHANGES_REQUESTED":
+		return "requested changes"
+	case "DISMISSED":
+		return "reviewed (dismissed)"
+	default:
+		return "commented"
+	}
+}
+
+func writeField(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "- **%s:** %s\n", name, value)
+	}
+}
+
+func writeBody(b *strings.Builder, body string) {
+	b.WriteString("\n## Description\n\n")
+	if body = strings.TrimSpace(body); body == "" {
+		body = "_No description provided._"
+	}
+	b.WriteString(body + "\n")
+}
+
+func writeComments(b *strings.Builder, comments []*github.IssueComment) {
+	if len(comments) == 0 {
+		b.WriteString("\n## Comments\n\n_No comments._\n")
+		return
+	}
+	fmt.Fprintf(b, "\n## Comments (%d)\n", len(comments))
+	for _, comment := range comments {
+		fmt.Fprintf(b, "\n### %s", mention(comment.GetUser()))
+		if when := formatTime(comment.CreatedAt); when != "" {
+			fmt.Fprintf(b, " on %s", when)
+		}
+		fmt.Fprintf(b, "\n\n%s\n", strings.TrimSpace(comment.GetBody()))
+	}
+}
+
+// mention is the @login of user, or "ghost" for a deleted account
+func mention(user *github.User) string {
+	if user.GetLogin() == "" {
+		return "ghost"
+	}
+	return "@" + user.GetLogin()
+}
+
+func labelList(labels []*github.Label) string {
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.GetName())
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// userList mentions users, falling back to the single legacy assignee field
+func userList(users []*github.User, fallback *github.User) string {
+	if len(users) == 0 && fallback != nil {
+		users = []*github.User{fallback}
+	}
+	var mentions []string
+	for _, user := range users {
+		mentions = append(mentions, mention(user))
+	}
+	if len(mentions) == 0 {
+		return "none"
+	}
+	return strings.Join(mentions, ", ")
+}
+
+func formatTime(ts *github.Timestamp) string {
+	if ts == nil || ts.IsZero() {
+		return ""
+	}
+	return ts.UTC().Format("2006-01-02 15:04 UTC")
+}
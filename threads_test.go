@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadIssueResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/issues/1")
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	text := contents[0].(mcp.TextResourceContents)
+	assert.Equal(t, "text/markdown", text.MIMEType)
+	assert.Contains(t, text.Text, "# Crash on startup (#1)")
+	assert.Contains(t, text.Text, "- **Labels:** none")
+	assert.Contains(t, text.Text, "- **Assignees:** @alice")
+	assert.Contains(t, text.Text, "The app exits before the window opens.")
+	assert.Contains(t, text.Text, "## Comments (1)")
+	assert.Contains(t, text.Text, "### @bob on 2025-05-29")
+	assert.Contains(t, text.Text, "Confirmed on 2.1")
+
+	contents, err = readResource(t, s, "github://acme/widgets/issues/2")
+	require.NoError(t, err)
+	text = contents[0].(mcp.TextResourceContents)
+	assert.Contains(t, text.Text, "- **Labels:** docs, good first issue")
+	assert.Contains(t, text.Text, "_No description provided._")
+	assert.Contains(t, text.Text, "_No comments._")
+}
+
+func TestReadPullRequestResource(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	contents, err := readResource(t, s, "github://acme/widgets/pulls/3")
+	require.NoError(t, err)
+	text := contents[0].(mcp.TextResourceContents).Text
+	assert.Contains(t, text, "# Add feature (#3)")
+	assert.Contains(t, text, "- **Branches:** `carol:feature` → `main`")
+	assert.Contains(t, text, "- **Review state:** approved\n  - @bob approved\n")
+	assert.Contains(t, text, "## Reviews (2)")
+	assert.Contains(t, text, "### @bob requested changes\n\nNeeds tests")
+
+	contents, err = readResource(t, s, "github://acme/widgets/pulls/4")
+	require.NoError(t, err)
+	text = contents[0].(mcp.TextResourceContents).Text
+	assert.Contains(t, text, "- **State:** open (draft)")
+	assert.Contains(t, text, "- **Review state:** no reviews yet")
+}
+
+func TestReadThreadResourceErrors(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	_, err := readResource(t, s, "github://acme/widgets/issues/99")
+	assert.ErrorContains(t, err, "not_found")
+	_, err = readResource(t, s, "github://acme/widgets/pulls/abc")
+	assert.ErrorContains(t, err, "expected github://{owner}/{repo}/pulls/{number}")
+
+	// The resources follow their toolsets
+	s, _ = newTestServerWith(t, serverOptions{Toolsets: []string{toolsetIssues}})
+	_, err = readResource(t, s, "github://acme/widgets/issues/1")
+	assert.NoError(t, err)
+	_, err = readResource(t, s, "github://acme/widgets/pulls/3")
+	assert.Error(t, err)
+}
+
+func TestReviewState(t *testing.T) {
+	review := func(login, state string) *github.PullRequestReview {
+		return &github.PullRequestReview{User: &github.User{Login: github.String(login)}, State: github.String(state)}
+	}
+
+	verdicts, state := reviewState([]*github.PullRequestReview{
+		review("alice", "APPROVED"),
+		review("bob", "CHANGES_REQUESTED"),
+		review("alice", "COMMENTED"),
+	})
+	assert.Equal(t, "changes requested", state)
+	assert.Equal(t, []string{"@alice approved", "@bob requested changes"}, verdicts)
+
+	verdicts, state = reviewState([]*github.PullRequestReview{
+		review("bob", "CHANGES_REQUESTED"),
+		review("bob", "DISMISSED"),
+		review("carol", "COMMENTED"),
+	})
+	assert.Equal(t, "reviewed, not yet approved", state)
+	assert.Empty(t, verdicts)
+}
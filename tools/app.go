@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// installationTokenRefreshMargin is how long before expiry a cached
+// installation token is replaced with a fresh one
+const installationTokenRefreshMargin = 5 * time.Minute
+
+// AppAuth signs GitHub App JWTs and exchanges them for installation tokens,
+// caching one token per host and installation until shortly before it expires
+type AppAuth struct {
+	AppID      int64
+	PrivateKey *rsa.PrivateKey
+	// InstallationID pins every owner to one installation. When zero the
+	// installation is looked up per owner.
+	InstallationID int64
+
+	now func() time.Time
+
+	// mu guards the caches and in-flight lookups below. It is never held
+	// across a request to GitHub.
+	mu            sync.Mutex
+	installations map[string]int64
+	// tokens is keyed by host and installation ID: an installation ID only
+	// means something on the host that issued it
+	tokens map[string]installationToken
+
+	installationLookups map[string]*flight[int64]
+	tokenExchanges      map[string]*flight[string]
+}
+
+// flight is a lookup in progress that concurrent callers for the same key wait on
+type flight[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// installationToken is a cached installation access token
+type installationToken struct {
+	token  string
+	expiry time.Time
+}
+
+// NewAppAuth creates an AppAuth from an app ID and a PEM encoded RSA private key
+func NewAppAuth(appID int64, privateKeyPEM []byte) (*AppAuth, error) {
+	key, err := parseRSAPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &AppAuth{
+		AppID:         appID,
+		PrivateKey:    key,
+		now:           time.Now,
+		installations: make(map[string]int64),
+		tokens:        make(map[string]installationToken),
+
+		installationLookups: make(map[string]*flight[int64]),
+		tokenExchanges:      make(map[string]*flight[string]),
+	}, nil
+}
+
+// installationToken returns a cached installation token for owner, creating
+// a new one through client (authenticated as the app on owner's host) when
+// none is cached or the cached one is about to expire
+func (a *AppAuth) installationToken(ctx context.Context, client *github.Client, owner string) (string, error) {
+	id, err := a.installationID(ctx, client, owner)
+	if err != nil {
+		return "", err
+	}
+
+	key := client.BaseURL.Host + "/" + strconv.FormatInt(id, 10)
+	a.mu.Lock()
+	tok, ok := a.tokens[key]
+	a.mu.Unlock()
+	if ok && a.now().Add(installationTokenRefreshMargin).Before(tok.expiry) {
+		return tok.token, nil
+	}
+
+	return shareFlight(ctx, &a.mu, a.tokenExchanges, key, func() (string, error) {
+		it, _, err := client.Apps.CreateInstallationToken(ctx, id, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create installation token for %s: %w", owner, err)
+		}
+		a.mu.Lock()
+		a.tokens[key] = installationToken{token: it.GetToken(), expiry: it.GetExpiresAt().Time}
+		a.mu.Unlock()
+		return it.GetToken(), nil
+	})
+}
+
+// installationID resolves the app installation covering owner, trying the
+// organization endpoint first and falling back to the user endpoint
+func (a *AppAuth) installationID(ctx context.Context, client *github.Client, owner string) (int64, error) {
+	if a.InstallationID != 0 {
+		return a.InstallationID, nil
+	}
+	key := client.BaseURL.Host + "/" + strings.ToLower(owner)
+	a.mu.Lock()
+	id, ok := a.installations[key]
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	return shareFlight(ctx, &a.mu, a.installationLookups, key, func() (int64, error) {
+		inst, _, err := client.Apps.FindOrganizationInstallation(ctx, owner)
+		if err != nil {
+			var userErr error
+			if inst, _, userErr = client.Apps.FindUserInstallation(ctx, owner); userErr != nil {
+				return 0, fmt.Errorf("GitHub App %d is not installed for %s: %w", a.AppID, owner, err)
+			}
+		}
+		a.mu.Lock()
+		a.installations[key] = inst.GetID()
+		a.mu.Unlock()
+		return inst.GetID(), nil
+	})
+}
+
+// shareFlight runs fetch for key unless a call for the same key is already in
+// flight, in which case it waits for that call's result instead. mu guards
+// flights and is only held to look up and record calls, never across fetch.
+func shareFlight[T any](ctx context.Context, mu *sync.Mutex, flights map[string]*flight[T], key string, fetch func() (T, error)) (T, error) {
+	mu.Lock()
+	if f, ok := flights[key]; ok {
+		mu.Unlock()
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+	f := &flight[T]{done: make(chan struct{})}
+	flights[key] = f
+	mu.Unlock()
+
+	f.value, f.err = fetch()
+
+	mu.Lock()
+	delete(flights, key)
+	mu.Unlock()
+	close(f.done)
+	return f.value, f.err
+}
+
+// appJWTTransport authenticates requests with a freshly signed app JWT
+type appJWTTransport struct {
+	app  *AppAuth
+	base http.RoundTripper
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.JWT()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// JWT returns an RS256 signed JSON Web Token identifying the app, valid for
+// nine minutes and backdated one minute to allow for clock drift
+func (a *AppAuth) JWT() (string, error) {
+	now := a.now()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.AppID, 10),
+	})
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// parseRSAPrivateKey decodes a PKCS#1 or PKCS#8 PEM encoded RSA private key
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestApp(t *testing.T) *AppAuth {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	app, err := NewAppAuth(42, keyPEM)
+	require.NoError(t, err)
+	return app
+}
+
+func TestAppJWT(t *testing.T) {
+	app := newTestApp(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	app.now = func() time.Time { return now }
+
+	token, err := app.JWT()
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&app.PrivateKey.PublicKey, crypto.SHA256, digest[:], sig))
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	require.NoError(t, json.Unmarshal(rawClaims, &claims))
+	assert.Equal(t, "42", claims.Iss)
+	assert.Equal(t, now.Add(-time.Minute).Unix(), claims.Iat)
+	assert.Equal(t, now.Add(9*time.Minute).Unix(), claims.Exp)
+}
+
+func TestAppInstallationTokenCachedAndRefreshed(t *testing.T) {
+	app := newTestApp(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	app.now = func() time.Time { return now }
+
+	var lookups, exchanges int
+	var repoAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		switch r.URL.Path {
+		case "/orgs/acme/installation":
+			lookups++
+			fmt.Fprint(w, `{"id": 7}`)
+		case "/app/installations/7/access_tokens":
+			exchanges++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`,
+				exchanges, now.Add(time.Hour).Format(time.RFC3339))
+		case "/repos/acme/widgets":
+			repoAuth = r.Header.Get("Authorization")
+			fmt.Fprint(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := NewService(Config{BaseURL: srv.URL, App: app})
+	require.NoError(t, err)
+	appClient := svc.github.Client().Transport.(*authTransport).appClient
+
+	token := func() string {
+		tok, err := app.installationToken(context.Background(), appClient, "acme")
+		require.NoError(t, err)
+		return tok
+	}
+	assert.Equal(t, "ghs_1", token())
+
+	// Still well within its lifetime: served from cache
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, "ghs_1", token())
+
+	// Inside the refresh margin: exchanged again
+	now = now.Add(26 * time.Minute)
+	assert.Equal(t, "ghs_2", token())
+
+	// Tool calls through the service use the cached installation token
+	ctx, client := svc.clientFor(context.Background(), "acme")
+	_, _, err = client.Repositories.Get(ctx, "acme", "widgets")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer ghs_2", repoAuth)
+
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, 2, exchanges)
+}
+
+func TestAppInstallationTokenPerHost(t *testing.T) {
+	app := newTestApp(t)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	app.now = func() time.Time { return now }
+	// The same installation ID on both hosts, as when it is pinned
+	app.InstallationID = 7
+
+	// host serves installation tokens named after it and records the
+	// Authorization header of its repository requests
+	host := func(name string, repoAuth *string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch strings.TrimPrefix(r.URL.Path, "/api/v3") {
+			case "/app/installations/7/access_tokens":
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token": "ghs_%s", "expires_at": %q}`, name, now.Add(time.Hour).Format(time.RFC3339))
+			case "/repos/acme/widgets", "/repos/golang/go":
+				*repoAuth = r.Header.Get("Authorization")
+				fmt.Fprint(w, `{}`)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	}
+	var dotcomAuth, enterpriseAuth string
+	dotcom := host("dotcom", &dotcomAuth)
+	defer dotcom.Close()
+	enterprise := host("enterprise", &enterpriseAuth)
+	defer enterprise.Close()
+
+	svc, err := NewService(Config{
+		BaseURL:    dotcom.URL,
+		Enterprise: EnterpriseConfig{BaseURL: enterprise.URL, Owners: []string{"acme"}},
+		App:        app,
+	})
+	require.NoError(t, err)
+
+	get := func(owner, repo string) {
+		ctx, client := svc.clientFor(context.Background(), owner)
+		_, _, err := client.Repositories.Get(ctx, owner, repo)
+		require.NoError(t, err)
+	}
+	get("golang", "go")
+	get("acme", "widgets")
+	// Served from cache the second time round, still per host
+	get("golang", "go")
+	get("acme", "widgets")
+
+	assert.Equal(t, "Bearer ghs_dotcom", dotcomAuth)
+	assert.Equal(t, "Bearer ghs_enterprise", enterpriseAuth)
+}
+
+func TestAppInstallationTokenExchangeOutsideLock(t *testing.T) {
+	app := newTestApp(t)
+
+	var mu sync.Mutex
+	exchanges := map[string]int{}
+	started, release := make(chan struct{}), make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/acme/installation":
+			fmt.Fprint(w, `{"id": 7}`)
+		case "/orgs/octo/installation":
+			fmt.Fprint(w, `{"id": 8}`)
+		case "/app/installations/7/access_tokens", "/app/installations/8/access_tokens":
+			mu.Lock()
+			exchanges[r.URL.Path]++
+			first := exchanges[r.URL.Path] == 1
+			mu.Unlock()
+			// acme's first exchange hangs until released
+			if first && strings.Contains(r.URL.Path, "/7/") {
+				close(started)
+				<-release
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_%s", "expires_at": %q}`,
+				strings.Split(r.URL.Path, "/")[3], time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc, err := NewService(Config{BaseURL: srv.URL, App: app})
+	require.NoError(t, err)
+	appClient := svc.github.Client().Transport.(*authTransport).appClient
+	token := func(owner string) (string, error) {
+		return app.installationToken(context.Background(), appClient, owner)
+	}
+	_, err = token("octo")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = token("acme")
+		}()
+	}
+	<-started
+
+	// A cached token for another owner is served while acme's exchange is in flight
+	done := make(chan string, 1)
+	go func() {
+		tok, _ := token("octo")
+		done <- tok
+	}()
+	select {
+	case tok := <-done:
+		assert.Equal(t, "ghs_8", tok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("a cached token waited on another owner's exchange")
+	}
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, []string{"ghs_7", "ghs_7", "ghs_7", "ghs_7", "ghs_7"}, tokens)
+	assert.Equal(t, 1, exchanges["/app/installations/7/access_tokens"], "concurrent callers share one exchange")
+	assert.Equal(t, 1, exchanges["/app/installations/8/access_tokens"])
+}
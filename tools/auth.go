@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// tokenKey is the context key holding the caller's GitHub token
+type tokenKey struct{}
+
+// ownerKey is the context key holding the owner a request is made for
+type ownerKey struct{}
+
+// WithToken returns a copy of ctx carrying the GitHub token of the caller.
+// Tool calls made with this context act with the caller's permissions instead
+// of the server identity. An empty token makes unauthenticated calls.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext returns the caller's GitHub token and whether one was set on ctx
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey{}).(string)
+	return token, ok
+}
+
+// withOwner records the owner a request is made for, used to pick a GitHub App installation
+func withOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerKey{}, owner)
+}
+
+func ownerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	return owner
+}
+
+// authTransport authenticates each request as the caller (see WithToken);
+// without a caller token it uses the GitHub App installation for the owner
+// when configured, or else the host's server token
+type authTransport struct {
+	base      http.RoundTripper
+	token     string
+	app       *AppAuth
+	appClient *github.Client
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, ok := TokenFromContext(ctx)
+	if !ok {
+		token = t.token
+		if t.app != nil {
+			tok, err := t.app.installationToken(ctx, t.appClient, ownerFromContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			token = tok
+		}
+	}
+
+	if token != "" {
+		req = req.Clone(ctx)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.base.RoundTrip(req)
+}
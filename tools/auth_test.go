@@ -0,0 +1,46 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenFromContext(t *testing.T) {
+	_, ok := TokenFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithToken(context.Background(), "caller-token")
+	token, ok := TokenFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "caller-token", token)
+}
+
+func TestServicePrefersCallerToken(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	svc, err := NewService(Config{BaseURL: srv.URL, Token: "server-token"})
+	require.NoError(t, err)
+
+	call := func(ctx context.Context) string {
+		ctx, client := svc.clientFor(ctx, "acme")
+		_, _, err := client.Repositories.Get(ctx, "acme", "widgets")
+		require.NoError(t, err)
+		return gotAuth
+	}
+
+	assert.Equal(t, "Bearer server-token", call(context.Background()))
+	assert.Equal(t, "Bearer caller-token", call(WithToken(context.Background(), "caller-token")))
+
+	// An explicitly empty caller token must not fall back to the server identity
+	assert.Equal(t, "", call(WithToken(context.Background(), "")))
+}
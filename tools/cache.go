@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheMaxBytes = 32 << 20
+	defaultCacheTTL      = 10 * time.Minute
+)
+
+// CacheConfig bounds the conditional-request response cache
+type CacheConfig struct {
+	// MaxBytes caps the total size of cached response bodies. Zero uses the
+	// default of 32 MiB; a negative value disables the cache.
+	MaxBytes int64
+	// TTL is how long a response is kept for revalidation. Zero uses the
+	// default of ten minutes.
+	TTL time.Duration
+}
+
+// cacheEntry is a stored GET response and the validators to revalidate it with
+type cacheEntry struct {
+	key      string
+	status   int
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+}
+
+// cacheTransport revalidates repeated GET requests with If-None-Match and
+// If-Modified-Since. GitHub answers unchanged resources with 304 Not Modified,
+// which does not count against the rate limit, and the stored body is served.
+// Entries are keyed by URL and credentials so callers never see each other's
+// responses, and evicted least recently used once MaxBytes is exceeded.
+type cacheTransport struct {
+	base   http.RoundTripper
+	config CacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+func newCacheTransport(base http.RoundTripper, config CacheConfig, now func() time.Time) http.RoundTripper {
+	if config.MaxBytes < 0 {
+		return base
+	}
+	if config.MaxBytes == 0 {
+		config.MaxBytes = defaultCacheMaxBytes
+	}
+	if config.TTL == 0 {
+		config.TTL = defaultCacheTTL
+	}
+	return &cacheTransport{
+		base:    base,
+		config:  config,
+		now:     now,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	entry := t.get(key)
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if etag := entry.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return entry.response(req, resp.Header), nil
+	}
+
+	if resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "") {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.put(&cacheEntry{key: key, status: resp.StatusCode, header: resp.Header.Clone(), body: body, storedAt: t.now()})
+	}
+	return resp, nil
+}
+
+// response rebuilds a stored response, refreshed with the rate limit headers
+// of the 304 that revalidated it
+func (e *cacheEntry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.header.Clone()
+	for k, v := range fresh {
+		if strings.HasPrefix(k, "X-Ratelimit-") || k == "Date" {
+			header[k] = v
+		}
+	}
+	return &http.Response{
+		Status:        http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func (t *cacheTransport) get(key string) *cacheEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	el, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*cacheEntry)
+	if t.now().Sub(entry.storedAt) > t.config.TTL {
+		t.remove(el)
+		return nil
+	}
+	t.lru.MoveToFront(el)
+	return entry
+}
+
+func (t *cacheTransport) put(entry *cacheEntry) {
+	size := int64(len(entry.body))
+	if size > t.config.MaxBytes {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.entries[entry.key]; ok {
+		t.remove(el)
+	}
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	t.size += size
+	for t.size > t.config.MaxBytes {
+		t.remove(t.lru.Back())
+	}
+}
+
+func (t *cacheTransport) remove(el *list.Element) {
+	entry := t.lru.Remove(el).(*cacheEntry)
+	delete(t.entries, entry.key)
+	t.size -= int64(len(entry.body))
+}
+
+// cacheKey identifies a response by URL and the credentials it was fetched with
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(sum[:]) + " " + req.URL.String()
+}
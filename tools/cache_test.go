@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etagServer serves a fixed body per path, answering matching If-None-Match with 304
+func etagServer(conditional *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf("%q", r.URL.Path)
+		if r.Header.Get("If-None-Match") == etag {
+			*conditional++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-RateLimit-Remaining", "4998")
+		fmt.Fprintf(w, `{"path": %q}`, r.URL.Path)
+	}))
+}
+
+func get(t *testing.T, client *http.Client, url, auth string) (string, *http.Response) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", auth)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp
+}
+
+func TestCacheServesNotModifiedFromMemory(t *testing.T) {
+	var conditional int
+	srv := etagServer(&conditional)
+	defer srv.Close()
+
+	client := &http.Client{Transport: newCacheTransport(http.DefaultTransport, CacheConfig{}, time.Now)}
+
+	body, _ := get(t, client, srv.URL+"/repos/acme/widgets/issues", "Bearer a")
+	assert.Equal(t, `{"path": "/repos/acme/widgets/issues"}`, body)
+	assert.Equal(t, 0, conditional)
+
+	body, resp := get(t, client, srv.URL+"/repos/acme/widgets/issues", "Bearer a")
+	assert.Equal(t, `{"path": "/repos/acme/widgets/issues"}`, body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, 1, conditional)
+
+	// A different identity never revalidates against someone else's entry
+	get(t, client, srv.URL+"/repos/acme/widgets/issues", "Bearer b")
+	assert.Equal(t, 1, conditional)
+}
+
+func TestCacheExpiresEntriesAfterTTL(t *testing.T) {
+	var conditional int
+	srv := etagServer(&conditional)
+	defer srv.Close()
+
+	now := time.Now()
+	client := &http.Client{Transport: newCacheTransport(http.DefaultTransport, CacheConfig{TTL: time.Minute}, func() time.Time { return now })}
+
+	get(t, client, srv.URL+"/a", "")
+	now = now.Add(2 * time.Minute)
+	get(t, client, srv.URL+"/a", "")
+	assert.Equal(t, 0, conditional)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var conditional int
+	srv := etagServer(&conditional)
+	defer srv.Close()
+
+	// Each body is 14 bytes, so only two fit
+	cache := newCacheTransport(http.DefaultTransport, CacheConfig{MaxBytes: 30}, time.Now).(*cacheTransport)
+	client := &http.Client{Transport: cache}
+
+	get(t, client, srv.URL+"/a", "")
+	get(t, client, srv.URL+"/b", "")
+	get(t, client, srv.URL+"/a", "")
+	get(t, client, srv.URL+"/c", "")
+	assert.Equal(t, 1, conditional)
+	assert.Equal(t, 2, cache.lru.Len())
+	assert.LessOrEqual(t, cache.size, int64(30))
+
+	// /b was least recently used and has been evicted
+	get(t, client, srv.URL+"/b", "")
+	assert.Equal(t, 1, conditional)
+}
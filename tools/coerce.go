@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// coerceInput normalizes the argument shapes agents commonly send in place of
+// the declared ones, so that "20" works as an integer, "true" as a boolean,
+// "bug, ci" or a single string as an array and ["open"] as a string. Values
+// that cannot be converted are left alone for validateInput to report.
+func coerceInput(schema *jsonschema.Schema, args map[string]interface{}) {
+	for name, value := range args {
+		if prop, ok := schema.Properties.Get(name); ok && value != nil {
+			args[name] = coerceValue(prop, value)
+		}
+	}
+}
+
+// coerceValue converts value to the type prop declares, when it unambiguously can
+func coerceValue(prop *jsonschema.Schema, value interface{}) interface{} {
+	switch prop.Type {
+	case "integer", "number":
+		switch v := value.(type) {
+		case string:
+			if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return n
+			}
+		case []interface{}:
+			if len(v) == 1 {
+				return coerceValue(prop, v[0])
+			}
+		}
+	case "boolean":
+		switch v := value.(type) {
+		case string:
+			if b, err := strconv.ParseBool(strings.ToLower(strings.TrimSpace(v))); err == nil {
+				return b
+			}
+		case []interface{}:
+			if len(v) == 1 {
+				return coerceValue(prop, v[0])
+			}
+		}
+	case "string":
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
+		case []interface{}:
+			var parts []string
+			for _, item := range v {
+				s, ok := coerceValue(prop, item).(string)
+				if !ok {
+					return value
+				}
+				parts = append(parts, s)
+			}
+			return strings.Join(parts, ",")
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			items = coerceArray(value)
+		}
+		if prop.Items == nil {
+			return items
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = coerceValue(prop.Items, item)
+		}
+		return out
+	}
+	return value
+}
+
+// coerceArray turns a scalar into an array: a JSON array encoded as a string
+// is decoded, any other string is split on commas, other values are wrapped
+func coerceArray(value interface{}) []interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return []interface{}{value}
+	}
+	if trimmed := strings.TrimSpace(s); strings.HasPrefix(trimmed, "[") {
+		var items []interface{}
+		if json.Unmarshal([]byte(trimmed), &items) == nil {
+			return items
+		}
+	}
+	items := []interface{}{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInputCoercesArguments(t *testing.T) {
+	params, err := DecodeInput[SearchIssuesInput](json.RawMessage(`{
+		"owner": "acme", "repo": ["widgets"], "query": "crash", "state": "closed",
+		"prioritize": "True", "page": "2", "per_page": " 50 ", "max_results": [10]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, SearchIssuesInput{
+		RepoInput:  RepoInput{Owner: "acme", Repo: "widgets"},
+		Query:      "crash",
+		State:      "closed",
+		Prioritize: true,
+		PageInput:  PageInput{Page: 2, PerPage: 50, MaxResults: 10},
+	}, params)
+}
+
+func TestDecodeInputCoercesArrays(t *testing.T) {
+	for input, want := range map[string][]string{
+		`"bug, ci,,"`:         {"bug", "ci"},
+		`"bug"`:               {"bug"},
+		`"[\"bug\", \"ci\"]"`: {"bug", "ci"},
+		`["bug", 42]`:         {"bug", "42"},
+		`42`:                  {"42"},
+		`""`:                  {},
+	} {
+		params, err := DecodeInput[CreateIssueInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "title": 7, "labels": ` + input + `}`))
+		require.NoError(t, err, input)
+		assert.Equal(t, want, params.Labels, input)
+		assert.Equal(t, "7", params.Title)
+	}
+}
+
+func TestDecodeInputCoercionLeavesInvalidValues(t *testing.T) {
+	_, err := DecodeInput[PriorityInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "limit": "twenty"}`))
+	assert.EqualError(t, err, `invalid arguments: limit must be an integer, got "twenty"`)
+
+	_, err = DecodeInput[CreateIssueInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "title": "Bug", "dry_run": "maybe"}`))
+	assert.EqualError(t, err, `invalid arguments: dry_run must be a boolean, got "maybe"`)
+
+	// Coerced numbers are still range checked
+	_, err = DecodeInput[PriorityInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "limit": "500"}`))
+	assert.EqualError(t, err, `invalid arguments: limit must be at most 100, got 500`)
+}
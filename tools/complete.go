@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+const (
+	defaultCompletionTTL = time.Minute
+	// maxCompletionPages bounds how many pages of names a completion lists
+	maxCompletionPages = 5
+)
+
+// completionCache keeps the candidate names behind completions for a short
+// while, since clients ask again on every keystroke
+type completionCache struct {
+	mu      sync.Mutex
+	entries map[string]completionEntry
+}
+
+type completionEntry struct {
+	names   []string
+	expires time.Time
+}
+
+// CompleteOwners suggests the authenticated user and the organizations it
+// belongs to that the policy allows reading
+func (s *Service) CompleteOwners(ctx context.Context, prefix string) ([]string, error) {
+	names, err := s.completionNames(ctx, "", "owners", func(ctx context.Context, client *github.Client) ([]string, error) {
+		var names []string
+		user, _, userErr := client.Users.Get(ctx, "")
+		if userErr == nil {
+			names = append(names, user.GetLogin())
+		}
+		orgs, err := listAll(func(opts github.ListOptions) ([]*github.Organization, *github.Response, error) {
+			return client.Organizations.List(ctx, "", &opts)
+		})
+		if err != nil && userErr != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			names = append(names, org.GetLogin())
+		}
+		return names, nil
+	})
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+
+	var allowed []string
+	for _, name := range names {
+		if s.authorize(operationRead, name, "") == nil {
+			allowed = append(allowed, name)
+		}
+	}
+	return matchPrefix(allowed, prefix), nil
+}
+
+// CompleteRepos suggests the repositories of owner the policy allows reading
+func (s *Service) CompleteRepos(ctx context.Context, owner, prefix string) ([]string, error) {
+	if owner == "" {
+		return nil, invalidInput("owner is required")
+	}
+	names, err := s.completionNames(ctx, owner, "repos "+owner, func(ctx context.Context, client *github.Client) ([]string, error) {
+		repos, err := listAll(func(opts github.ListOptions) ([]*github.Repository, *github.Response, error) {
+			return client.Repositories.ListByOrg(ctx, owner, &github.RepositoryListByOrgOptions{ListOptions: opts})
+		})
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response.StatusCode == http.StatusNotFound {
+			// Not an organization: a user, who sees their private repositories too
+			user := owner
+			if strings.EqualFold(owner, s.Identity(ctx, owner)) {
+				user = ""
+			}
+			repos, err = listAll(func(opts github.ListOptions) ([]*github.Repository, *github.Response, error) {
+				return client.Repositories.List(ctx, user, &github.RepositoryListOptions{Affiliation: affiliation(user), ListOptions: opts})
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.GetName())
+		}
+		return names, nil
+	})
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+
+	var allowed []string
+	for _, name := range names {
+		if s.authorize(operationRead, owner, name) == nil {
+			allowed = append(allowed, name)
+		}
+	}
+	return matchPrefix(allowed, prefix), nil
+}
+
+// CompleteLabels suggests the labels defined in owner/repo
+func (s *Service) CompleteLabels(ctx context.Context, owner, repo, prefix string) ([]string, error) {
+	return s.completeRepoNames(ctx, owner, repo, "labels", prefix, func(ctx context.Context, client *github.Client) ([]string, error) {
+		labels, err := listAll(func(opts github.ListOptions) ([]*github.Label, *github.Response, error) {
+			return client.Issues.ListLabels(ctx, owner, repo, &opts)
+		})
+		var names []string
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+		return names, err
+	})
+}
+
+// CompleteAssignees suggests the logins that can be assigned issues in owner/repo
+func (s *Service) CompleteAssignees(ctx context.Context, owner, repo, prefix string) ([]string, error) {
+	return s.completeRepoNames(ctx, owner, repo, "assignees", prefix, func(ctx context.Context, client *github.Client) ([]string, error) {
+		users, err := listAll(func(opts github.ListOptions) ([]*github.User, *github.Response, error) {
+			return client.Issues.ListAssignees(ctx, owner, repo, &opts)
+		})
+		var names []string
+		for _, user := range users {
+			names = append(names, user.GetLogin())
+		}
+		return names, err
+	})
+}
+
+// completeRepoNames completes names listed from within owner/repo
+func (s *Service) completeRepoNames(ctx context.Context, owner, repo, kind, prefix string, load func(context.Context, *github.Client) ([]string, error)) ([]string, error) {
+	if owner == "" || repo == "" {
+		return nil, invalidInput("owner and repo are required")
+	}
+	if err := s.authorize(operationRead, owner, repo); err != nil {
+		return nil, err
+	}
+	names, err := s.completionNames(ctx, owner, kind+" "+owner+"/"+repo, load)
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+	return matchPrefix(names, prefix), nil
+}
+
+// completionNames returns the cached names for key, loading them with the
+// client for owner once they are older than the completion TTL. Entries are
+// kept per identity so callers never see names only another token can see.
+func (s *Service) completionNames(ctx context.Context, owner, key string, load func(context.Context, *github.Client) ([]string, error)) ([]string, error) {
+	key = s.Identity(ctx, owner) + " " + strings.ToLower(key)
+	now := s.now()
+
+	s.completions.mu.Lock()
+	entry, ok := s.completions.entries[key]
+	s.completions.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.names, nil
+	}
+
+	ctx, client := s.clientFor(ctx, owner)
+	names, err := load(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.CompletionTTL > 0 {
+		s.completions.mu.Lock()
+		if s.completions.entries == nil {
+			s.completions.entries = make(map[string]completionEntry)
+		}
+		s.completions.entries[key] = completionEntry{names: names, expires: now.Add(s.config.CompletionTTL)}
+		s.completions.mu.Unlock()
+	}
+	return names, nil
+}
+
+// listAll collects up to maxCompletionPages pages of a listing
+func listAll[T any](list func(github.ListOptions) ([]T, *github.Response, error)) ([]T, error) {
+	var all []T
+	opts := github.ListOptions{PerPage: maxPerPage}
+	for page := 0; page < maxCompletionPages; page++ {
+		items, resp, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// matchPrefix returns the names starting with prefix, then those containing
+// it elsewhere, each group sorted and compared case-insensitively
+func matchPrefix(names []string, prefix string) []string {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	var starts, contains []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		switch {
+		case strings.HasPrefix(lower, prefix):
+			starts = append(starts, name)
+		case strings.Contains(lower, prefix):
+			contains = append(contains, name)
+		}
+	}
+	sortFold := func(list []string) {
+		sort.Slice(list, func(i, j int) bool { return strings.ToLower(list[i]) < strings.ToLower(list[j]) })
+	}
+	sortFold(starts)
+	sortFold(contains)
+	return append(starts, contains...)
+}
+
+// affiliation lists the authenticated user's own repositories; other users' listings take none
+func affiliation(user string) string {
+	if user == "" {
+		return "owner"
+	}
+	return ""
+}
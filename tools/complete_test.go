@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools/githubtest"
+)
+
+// newCompletionService returns a Service authenticated as octocat, a member of
+// acme, against a fake seeded with fakeRepo and a few more repositories
+func newCompletionService(t *testing.T, now *time.Time) (*Service, *githubtest.Server) {
+	t.Helper()
+	srv := githubtest.NewServer(fakeRepo(),
+		&githubtest.Repo{Owner: "acme", Name: "gadgets"},
+		&githubtest.Repo{Owner: "acme", Name: "secret-plans"},
+		&githubtest.Repo{Owner: "octocat", Name: "dotfiles"},
+	)
+	t.Cleanup(srv.Close)
+	srv.AddUser("tok", "octocat")
+	srv.AddOrgMember("acme", "octocat")
+
+	svc, err := NewService(Config{BaseURL: srv.URL, Token: "tok", Now: func() time.Time { return *now }})
+	require.NoError(t, err)
+	return svc, srv
+}
+
+func TestCompleteOwnersAndRepos(t *testing.T) {
+	now := fakeNow
+	svc, _ := newCompletionService(t, &now)
+	ctx := context.Background()
+
+	owners, err := svc.CompleteOwners(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme", "octocat"}, owners)
+
+	repos, err := svc.CompleteRepos(ctx, "acme", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gadgets", "secret-plans", "widgets"}, repos)
+
+	// Prefix matches come before matches elsewhere in the name
+	repos, err = svc.CompleteRepos(ctx, "acme", "GE")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gadgets", "widgets"}, repos)
+
+	// octocat is a user, not an organization
+	repos, err = svc.CompleteRepos(ctx, "octocat", "dot")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dotfiles"}, repos)
+}
+
+func TestCompleteOwnersFollowsPolicy(t *testing.T) {
+	now := fakeNow
+	svc, _ := newCompletionService(t, &now)
+	svc.config.Policy = Policy{Read: PolicyRules{Deny: []string{"acme"}}}
+
+	owners, err := svc.CompleteOwners(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"octocat"}, owners)
+}
+
+func TestCompleteReposFollowsPolicy(t *testing.T) {
+	now := fakeNow
+	svc, _ := newCompletionService(t, &now)
+	svc.config.Policy = Policy{Read: PolicyRules{Deny: []string{"acme/secret-*"}}}
+
+	repos, err := svc.CompleteRepos(context.Background(), "acme", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gadgets", "widgets"}, repos)
+
+	_, err = svc.CompleteLabels(context.Background(), "acme", "secret-plans", "")
+	var policyErr *PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+}
+
+func TestCompleteLabelsAndAssignees(t *testing.T) {
+	now := fakeNow
+	svc, _ := newCompletionService(t, &now)
+	ctx := context.Background()
+
+	labels, err := svc.CompleteLabels(ctx, "acme", "widgets", "p")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p0"}, labels)
+
+	assignees, err := svc.CompleteAssignees(ctx, "acme", "widgets", "OCTO")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"octocat"}, assignees)
+
+	_, err = svc.CompleteLabels(ctx, "acme", "missing", "")
+	assert.Error(t, err)
+}
+
+func TestCompletionsAreCachedBriefly(t *testing.T) {
+	now := fakeNow
+	svc, srv := newCompletionService(t, &now)
+	ctx := context.Background()
+
+	countLabelRequests := func() int {
+		n := 0
+		for _, req := range srv.Requests {
+			if req == "GET /repos/acme/widgets/labels" {
+				n++
+			}
+		}
+		return n
+	}
+
+	for _, prefix := range []string{"", "b", "bu"} {
+		_, err := svc.CompleteLabels(ctx, "acme", "widgets", prefix)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, countLabelRequests())
+
+	now = now.Add(defaultCompletionTTL + time.Second)
+	_, err := svc.CompleteLabels(ctx, "acme", "widgets", "b")
+	require.NoError(t, err)
+	assert.Equal(t, 2, countLabelRequests())
+}
+
+func TestMatchPrefix(t *testing.T) {
+	names := []string{"type: bug", "Bug", "bugfix", "docs", "bug"}
+	assert.Equal(t, []string{"Bug", "bugfix", "type: bug"}, matchPrefix(names, "bug"))
+	assert.Equal(t, []string{"Bug", "bugfix", "docs", "type: bug"}, matchPrefix(names, ""))
+}
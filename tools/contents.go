@@ -0,0 +1,42 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// GetContents reads path in owner/repo at ref, or the default branch when ref
+// is empty. A file is returned on its own; a directory as its entries.
+func (s *Service) GetContents(ctx context.Context, owner, repo, path, ref string) (*github.RepositoryContent, []*github.RepositoryContent, error) {
+	if owner == "" || repo == "" {
+		return nil, nil, invalidInput("owner and repo are required")
+	}
+	if err := s.authorize(operationRead, owner, repo); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, owner)
+	file, dir, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, nil, s.rateLimited(err)
+	}
+	return file, dir, nil
+}
+
+// GetReadme returns the README of owner/repo at ref, or the default branch when ref is empty
+func (s *Service) GetReadme(ctx context.Context, owner, repo, ref string) (*github.RepositoryContent, error) {
+	if owner == "" || repo == "" {
+		return nil, invalidInput("owner and repo are required")
+	}
+	if err := s.authorize(operationRead, owner, repo); err != nil {
+		return nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, owner)
+	readme, _, err := client.Repositories.GetReadme(ctx, owner, repo, &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+	return readme, nil
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeGetContents(t *testing.T) {
+	svc, _ := newFakeService(t)
+	ctx := context.Background()
+
+	file, dir, err := svc.GetContents(ctx, "acme", "widgets", "docs/guide.md", "")
+	require.NoError(t, err)
+	assert.Nil(t, dir)
+	content, err := file.GetContent()
+	require.NoError(t, err)
+	assert.Equal(t, "Read me first", content)
+
+	file, dir, err = svc.GetContents(ctx, "acme", "widgets", "docs", "")
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	require.Len(t, dir, 2)
+	assert.Equal(t, "dir", dir[0].GetType())
+	assert.Equal(t, "docs/api", dir[0].GetPath())
+	assert.Equal(t, "docs/guide.md", dir[1].GetPath())
+
+	file, _, err = svc.GetContents(ctx, "acme", "widgets", "README.md", "v1.0")
+	require.NoError(t, err)
+	content, _ = file.GetContent()
+	assert.Equal(t, "# Widgets 1.0\n", content)
+
+	_, _, err = svc.GetContents(ctx, "acme", "widgets", "missing.md", "")
+	assert.Error(t, err)
+}
+
+func TestFakeGetReadme(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	readme, err := svc.GetReadme(context.Background(), "acme", "widgets", "")
+	require.NoError(t, err)
+	assert.Equal(t, "README.md", readme.GetPath())
+}
+
+func TestGetContentsChecksPolicy(t *testing.T) {
+	svc, srv := newFakeService(t)
+	svc.config.Policy = Policy{Read: PolicyRules{Deny: []string{"acme/widgets"}}}
+
+	_, _, err := svc.GetContents(context.Background(), "acme", "widgets", "README.md", "")
+	var policyErr *PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	_, err = svc.GetReadme(context.Background(), "acme", "widgets", "")
+	require.ErrorAs(t, err, &policyErr)
+	assert.Empty(t, srv.Requests)
+}
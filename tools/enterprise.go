@@ -0,0 +1,49 @@
+package tools
+
+import "strings"
+
+// EnterpriseConfig describes a GitHub Enterprise Server instance and which
+// owners live on it. Owners not listed are served from github.com.
+type EnterpriseConfig struct {
+	// BaseURL is the GHES address, e.g. https://github.example.com.
+	// The /api/v3/ suffix is added automatically when missing.
+	BaseURL string `yaml:"base_url"`
+	// UploadURL defaults to BaseURL; /api/uploads/ is added automatically.
+	UploadURL string `yaml:"upload_url"`
+	// Token authenticates against the enterprise host
+	Token string `yaml:"token"`
+	// ShareToken sends the github.com token to the enterprise host when no
+	// Token is set. Off by default so one host's credential never reaches another.
+	ShareToken bool `yaml:"share_token"`
+	// Owners routed to the enterprise host. When empty, every owner is.
+	Owners []string `yaml:"owners"`
+}
+
+// Enabled reports whether an enterprise host is configured
+func (c EnterpriseConfig) Enabled() bool {
+	return c.BaseURL != ""
+}
+
+// Routes reports whether requests for owner should go to the enterprise host
+func (c EnterpriseConfig) Routes(owner string) bool {
+	if !c.Enabled() {
+		return false
+	}
+	if len(c.Owners) == 0 {
+		return true
+	}
+	for _, o := range c.Owners {
+		if strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadURL returns the configured upload URL, defaulting to the base URL
+func (c EnterpriseConfig) uploadURL() string {
+	if c.UploadURL != "" {
+		return c.UploadURL
+	}
+	return c.BaseURL
+}
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnterpriseRoutes(t *testing.T) {
+	assert.False(t, EnterpriseConfig{}.Routes("acme"))
+
+	all := EnterpriseConfig{BaseURL: "https://ghe.example.com"}
+	assert.True(t, all.Routes("acme"))
+
+	some := EnterpriseConfig{BaseURL: "https://ghe.example.com", Owners: []string{"acme", "Platform"}}
+	assert.True(t, some.Routes("ACME"))
+	assert.True(t, some.Routes("platform"))
+	assert.False(t, some.Routes("golang"))
+}
+
+func TestServiceEnterpriseURLs(t *testing.T) {
+	svc, err := NewService(Config{
+		Enterprise: EnterpriseConfig{BaseURL: "https://ghe.example.com", Owners: []string{"acme"}},
+	})
+	require.NoError(t, err)
+
+	_, client := svc.clientFor(context.Background(), "acme")
+	assert.Equal(t, "https://ghe.example.com/api/v3/", client.BaseURL.String())
+	assert.Equal(t, "https://ghe.example.com/api/uploads/", client.UploadURL.String())
+
+	_, client = svc.clientFor(context.Background(), "golang")
+	assert.Equal(t, "https://api.github.com/", client.BaseURL.String())
+}
+
+func TestEnterpriseTokenNotSharedByDefault(t *testing.T) {
+	enterprise := EnterpriseConfig{BaseURL: "https://ghe.example.com"}
+	serverToken := func(cfg Config) string {
+		svc, err := NewService(cfg)
+		require.NoError(t, err)
+		return svc.enterprise.Client().Transport.(*authTransport).token
+	}
+
+	_, err := NewService(Config{Token: "dotcom-token", Enterprise: enterprise})
+	assert.ErrorContains(t, err, "enterprise host is configured without its own token")
+
+	withToken := enterprise
+	withToken.Token = "ghes-token"
+	assert.Equal(t, "ghes-token", serverToken(Config{Token: "dotcom-token", Enterprise: withToken}))
+
+	shared := enterprise
+	shared.ShareToken = true
+	assert.Equal(t, "dotcom-token", serverToken(Config{Token: "dotcom-token", Enterprise: shared}))
+
+	// Nothing to leak without a github.com token
+	assert.Empty(t, serverToken(Config{Enterprise: enterprise}))
+}
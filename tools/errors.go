@@ -0,0 +1,18 @@
+package tools
+
+import "fmt"
+
+// InputError reports tool arguments that are malformed, missing or out of
+// range. Calling again with the same arguments fails the same way.
+type InputError struct {
+	msg string
+}
+
+func (e *InputError) Error() string {
+	return e.msg
+}
+
+// invalidInput returns an *InputError with a formatted message
+func invalidInput(format string, args ...interface{}) error {
+	return &InputError{msg: fmt.Sprintf(format, args...)}
+}
@@ -4,95 +4,84 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v56/github"
-	"golang.org/x/oauth2"
 )
 
-var GitHubClient = func(ctx context.Context) *github.Client {
-	token := os.Getenv("GITHUB_TOKEN")
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
-}
-
-type ToolInput struct {
-	Owner    string   `json:"owner"`
-	Repo     string   `json:"repo"`
-	State    string   `json:"state"`
-	Query    string   `json:"query"`
-	Title    string   `json:"title"`
-	Body     string   `json:"body"`
-	Labels   []string `json:"labels"`
-	Assignee string   `json:"assignee"`
-	Limit    int      `json:"limit"`
-	Prioritize bool   `json:"prioritize"`
-}
+// GetOpenIssues lists issues in a repository, excluding pull requests.
+// It returns a cursor for the next page, or "" when there are no more issues.
+func (s *Service) GetOpenIssues(ctx context.Context, input json.RawMessage) ([]*github.Issue, string, error) {
+	params, err := DecodeInput[ListIssuesInput](input)
+	if err != nil {
+		return nil, "", err
+	}
 
-func GetOpenIssues(ctx context.Context, input json.RawMessage) ([]*github.Issue, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
-		return nil, err
+	if err := s.authorize(operationRead, params.Owner, params.Repo); err != nil {
+		return nil, "", err
 	}
 
-	client := GitHubClient(ctx)
+	ctx, client := s.clientFor(ctx, params.Owner)
 	if params.State == "" {
 		params.State = "open"
 	}
-	
-	issues, _, err := client.Issues.ListByRepo(ctx, params.Owner, params.Repo, &github.IssueListByRepoOptions{
-		State: params.State,
-		ListOptions: github.ListOptions{PerPage: 100},
+
+	issues, next, err := paginate(ctx, s.pageDefaults(params.PageInput), "issues", func(opts github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		return client.Issues.ListByRepo(ctx, params.Owner, params.Repo, &github.IssueListByRepoOptions{
+			State:       params.State,
+			ListOptions: opts,
+		})
+	}, func(issue *github.Issue) bool {
+		// Filter out pull requests (GitHub API returns PRs as issues)
+		return !issue.IsPullRequest()
 	})
-	if err != nil {
-		return nil, err
-	}
+	return issues, next, s.rateLimited(err)
+}
 
-	// Filter out pull requests (GitHub API returns PRs as issues)
-	var actualIssues []*github.Issue
-	for _, issue := range issues {
-		if !issue.IsPullRequest() {
-			actualIssues = append(actualIssues, issue)
-		}
+// GetOpenPRs lists pull requests in a repository.
+// It returns a cursor for the next page, or "" when there are no more pull requests.
+func (s *Service) GetOpenPRs(ctx context.Context, input json.RawMessage) ([]*github.PullRequest, string, error) {
+	params, err := DecodeInput[ListPullRequestsInput](input)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return actualIssues, nil
-}
-
-func GetOpenPRs(ctx context.Context, input json.RawMessage) ([]*github.PullRequest, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
-		return nil, err
+	if err := s.authorize(operationRead, params.Owner, params.Repo); err != nil {
+		return nil, "", err
 	}
 
-	client := GitHubClient(ctx)
+	ctx, client := s.clientFor(ctx, params.Owner)
 	if params.State == "" {
 		params.State = "open"
 	}
-	
-	prs, _, err := client.PullRequests.List(ctx, params.Owner, params.Repo, &github.PullRequestListOptions{
-		State: params.State,
-		ListOptions: github.ListOptions{PerPage: 100},
-	})
-	if err != nil {
-		return nil, err
-	}
 
-	return prs, nil
+	prs, next, err := paginate(ctx, s.pageDefaults(params.PageInput), "PRs", func(opts github.ListOptions) ([]*github.PullRequest, *github.Response, error) {
+		return client.PullRequests.List(ctx, params.Owner, params.Repo, &github.PullRequestListOptions{
+			State:       params.State,
+			ListOptions: opts,
+		})
+	}, nil)
+	return prs, next, s.rateLimited(err)
 }
 
-// SearchIssues searches for issues by keyword/topic in title and body
-func SearchIssues(ctx context.Context, input json.RawMessage) ([]*github.Issue, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
-		return nil, err
+// SearchIssues searches for issues by keyword/topic in title and body.
+// It returns a cursor for the next page, or "" when there are no more results.
+func (s *Service) SearchIssues(ctx context.Context, input json.RawMessage) ([]*github.Issue, string, error) {
+	params, err := DecodeInput[SearchIssuesInput](input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.authorize(operationRead, params.Owner, params.Repo); err != nil {
+		return nil, "", err
+	}
+	if err := s.authorizeQuery(params.Query); err != nil {
+		return nil, "", err
 	}
 
-	client := GitHubClient(ctx)
+	ctx, client := s.clientFor(ctx, params.Owner)
 	if params.State == "" {
 		params.State = "open"
 	}
@@ -101,39 +90,54 @@ func SearchIssues(ctx context.Context, input json.RawMessage) ([]*github.Issue,
 	query := fmt.Sprintf("%s repo:%s/%s type:issue state:%s",
 		params.Query, params.Owner, params.Repo, params.State)
 
-	searchResult, _, err := client.Search.Issues(ctx, query, &github.SearchOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
-	})
+	issues, next, err := paginate(ctx, s.pageDefaults(params.PageInput), "issues", func(opts github.ListOptions) ([]*github.Issue, *github.Response, error) {
+		searchResult, resp, err := client.Search.Issues(ctx, query, &github.SearchOptions{
+			ListOptions: opts,
+		})
+		if err != nil {
+			return nil, resp, err
+		}
+		return searchResult.Issues, resp, nil
+	}, nil)
+	return issues, next, s.rateLimited(err)
+}
+
+// GetPendingReviews returns PRs that are open and potentially need review
+func (s *Service) GetPendingReviews(ctx context.Context, input json.RawMessage) ([]*github.PullRequest, error) {
+	params, err := DecodeInput[PendingReviewsInput](input)
 	if err != nil {
 		return nil, err
 	}
 
-	return searchResult.Issues, nil
-}
-
-// GetPendingReviews returns PRs that are open and potentially need review
-func GetPendingReviews(ctx context.Context, input json.RawMessage) ([]*github.PullRequest, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
+	if err := s.authorize(operationRead, params.Owner, params.Repo); err != nil {
 		return nil, err
 	}
 
-	client := GitHubClient(ctx)
-	
+	ctx, client := s.clientFor(ctx, params.Owner)
+
 	prs, _, err := client.PullRequests.List(ctx, params.Owner, params.Repo, &github.PullRequestListOptions{
-		State: "open",
+		State:       "open",
 		ListOptions: github.ListOptions{PerPage: 100},
 	})
 	if err != nil {
-		return nil, err
+		return nil, s.rateLimited(err)
 	}
+	reportProgress(ctx, 0, len(prs), "fetched %d open PRs, checking their reviews", len(prs))
 
 	// Filter for PRs that might need review
 	var pendingReviews []*github.PullRequest
-	for _, pr := range prs {
+	for i, pr := range prs {
+		if i > 0 {
+			reportProgress(ctx, i, len(prs), "checked %d/%d PRs", i, len(prs))
+		}
+
 		// Get review status for each PR
 		reviews, _, err := client.PullRequests.ListReviews(ctx, params.Owner, params.Repo, pr.GetNumber(), nil)
 		if err != nil {
+			// Retries are exhausted by now; stop rather than guess for every remaining PR
+			if limited := s.rateLimited(err); limited != err {
+				return nil, limited
+			}
 			// If we can't get reviews, assume it needs review
 			pendingReviews = append(pendingReviews, pr)
 			continue
@@ -153,59 +157,170 @@ func GetPendingReviews(ctx context.Context, input json.RawMessage) ([]*github.Pu
 			pendingReviews = append(pendingReviews, pr)
 		}
 	}
+	if len(prs) > 0 {
+		reportProgress(ctx, len(prs), len(prs), "checked %d/%d PRs", len(prs), len(prs))
+	}
 
 	return pendingReviews, nil
 }
 
 // CreateIssue creates a new GitHub issue
-func CreateIssue(ctx context.Context, input json.RawMessage) (*github.Issue, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
+func (s *Service) CreateIssue(ctx context.Context, input json.RawMessage) (*github.Issue, error) {
+	params, err := DecodeInput[CreateIssueInput](input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(operationWrite, params.Owner, params.Repo); err != nil {
+		return nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, params.Owner)
+
+	issue, _, err := client.Issues.Create(ctx, params.Owner, params.Repo, newIssueRequest(params))
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+
+	return issue, nil
+}
+
+// IssuePreview is the request CreateIssue would send for an input, with any
+// problems found resolving its labels and assignee against the repository
+type IssuePreview struct {
+	Request  *github.IssueRequest
+	Warnings []string
+}
+
+// PreviewIssue validates a create_issue input and resolves its labels and
+// assignee against the repository, without creating anything
+func (s *Service) PreviewIssue(ctx context.Context, input json.RawMessage) (*IssuePreview, error) {
+	params, err := DecodeInput[CreateIssueInput](input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(operationWrite, params.Owner, params.Repo); err != nil {
 		return nil, err
 	}
 
-	client := GitHubClient(ctx)
+	ctx, client := s.clientFor(ctx, params.Owner)
+	issueRequest := newIssueRequest(params)
+	preview := &IssuePreview{Request: issueRequest}
 
+	repo, _, err := client.Repositories.Get(ctx, params.Owner, params.Repo)
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+	if !repo.GetHasIssues() {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("issues are disabled in %s/%s", params.Owner, params.Repo))
+	}
+
+	if labels := issueRequest.GetLabels(); len(labels) > 0 {
+		existing := make(map[string]bool)
+		opts := &github.ListOptions{PerPage: 100}
+		for {
+			labels, resp, err := client.Issues.ListLabels(ctx, params.Owner, params.Repo, opts)
+			if err != nil {
+				return nil, s.rateLimited(err)
+			}
+			for _, label := range labels {
+				existing[strings.ToLower(label.GetName())] = true
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			reportProgress(ctx, len(existing), 0, "checked %d labels", len(existing))
+			opts.Page = resp.NextPage
+		}
+		for _, label := range labels {
+			if !existing[strings.ToLower(label)] {
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+					"label %q does not exist in %s/%s; GitHub creates it if you have push access and drops it otherwise",
+					label, params.Owner, params.Repo))
+			}
+		}
+	}
+
+	if params.Assignee != "" {
+		assignable, _, err := client.Issues.IsAssignee(ctx, params.Owner, params.Repo, params.Assignee)
+		if err != nil {
+			return nil, s.rateLimited(err)
+		}
+		if !assignable {
+			preview.Warnings = append(preview.Warnings, fmt.Sprintf(
+				"%s cannot be assigned issues in %s/%s; GitHub will reject the request or drop the assignee",
+				params.Assignee, params.Owner, params.Repo))
+		}
+	}
+
+	return preview, nil
+}
+
+// newIssueRequest builds the request create_issue sends to GitHub for a validated input
+func newIssueRequest(params CreateIssueInput) *github.IssueRequest {
 	issueRequest := &github.IssueRequest{
 		Title: &params.Title,
 		Body:  &params.Body,
 	}
 
-	if len(params.Labels) > 0 {
-		issueRequest.Labels = &params.Labels
+	var labels []string
+	for _, label := range params.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	if len(labels) > 0 {
+		issueRequest.Labels = &labels
 	}
 
 	if params.Assignee != "" {
 		issueRequest.Assignee = &params.Assignee
 	}
 
-	issue, _, err := client.Issues.Create(ctx, params.Owner, params.Repo, issueRequest)
-	if err != nil {
-		return nil, err
-	}
+	return issueRequest
+}
 
-	return issue, nil
+// Priority categories assigned by AnalyzeIssuePriority, most urgent first
+const (
+	PriorityCritical = "critical"
+	PriorityHigh     = "high"
+	PriorityMedium   = "medium"
+	PriorityLow      = "low"
+)
+
+// ScoredIssue is an open issue with the score and priority category
+// AnalyzeIssuePriority gave it
+type ScoredIssue struct {
+	Issue    *github.Issue
+	Score    int
+	Category string
 }
 
-// AnalyzeIssuePriority analyzes issues and categorizes them by priority
-func AnalyzeIssuePriority(ctx context.Context, input json.RawMessage) (map[string][]map[string]interface{}, error) {
-	var params ToolInput
-	if err := json.Unmarshal(input, &params); err != nil {
+// AnalyzeIssuePriority scores open issues and categorizes them by priority,
+// highest score first
+func (s *Service) AnalyzeIssuePriority(ctx context.Context, input json.RawMessage) ([]ScoredIssue, error) {
+	params, err := DecodeInput[PriorityInput](input)
+	if err != nil {
 		return nil, err
 	}
 
 	if params.Limit == 0 {
-		params.Limit = 20
+		params.Limit = s.config.Priority.Limit
 	}
 
-	client := GitHubClient(ctx)
-	
+	if err := s.authorize(operationRead, params.Owner, params.Repo); err != nil {
+		return nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, params.Owner)
+
 	issues, _, err := client.Issues.ListByRepo(ctx, params.Owner, params.Repo, &github.IssueListByRepoOptions{
-		State: "open",
+		State:       "open",
 		ListOptions: github.ListOptions{PerPage: params.Limit},
 	})
 	if err != nil {
-		return nil, err
+		return nil, s.rateLimited(err)
 	}
 
 	// Filter out pull requests
@@ -215,57 +330,36 @@ func AnalyzeIssuePriority(ctx context.Context, input json.RawMessage) (map[strin
 			actualIssues = append(actualIssues, issue)
 		}
 	}
+	reportProgress(ctx, 1, 2, "fetched %d open issues, scoring them", len(actualIssues))
 
-	// Calculate priority scores and categorize
-	type issueWithScore struct {
-		issue *github.Issue
-		score int
-	}
-
-	var issuesWithScores []issueWithScore
+	// Calculate priority scores and categorize based on score and labels
+	thresholds := s.config.Priority
+	scored := make([]ScoredIssue, 0, len(actualIssues))
 	for _, issue := range actualIssues {
-		score := calculatePriorityScore(issue)
-		issuesWithScores = append(issuesWithScores, issueWithScore{issue, score})
+		item := ScoredIssue{Issue: issue, Score: calculatePriorityScore(issue, s.now())}
+		switch {
+		case item.Score >= thresholds.Critical || hasLabel(issue, []string{"critical", "urgent", "p0"}):
+			item.Category = PriorityCritical
+		case item.Score >= thresholds.High || hasLabel(issue, []string{"high", "important", "p1"}):
+			item.Category = PriorityHigh
+		case item.Score >= thresholds.Medium || hasLabel(issue, []string{"medium", "p2"}):
+			item.Category = PriorityMedium
+		default:
+			item.Category = PriorityLow
+		}
+		scored = append(scored, item)
 	}
 
 	// Sort by priority score
-	sort.Slice(issuesWithScores, func(i, j int) bool {
-		return issuesWithScores[i].score > issuesWithScores[j].score
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
 	})
+	reportProgress(ctx, 2, 2, "scored %d issues", len(scored))
 
-	// Categorize by priority
-	result := make(map[string][]map[string]interface{})
-	result["🔴 critical"] = []map[string]interface{}{}
-	result["🟡 high"] = []map[string]interface{}{}
-	result["🟢 medium"] = []map[string]interface{}{}
-	result["⚪ low"] = []map[string]interface{}{}
-
-	for _, item := range issuesWithScores {
-		issueInfo := map[string]interface{}{
-			"number":         item.issue.GetNumber(),
-			"title":          item.issue.GetTitle(),
-			"priority_score": item.score,
-			"comments":       item.issue.GetComments(),
-			"reactions":      item.issue.GetReactions().GetTotalCount(),
-			"url":           item.issue.GetHTMLURL(),
-		}
-
-		// Categorize based on score and labels
-		if item.score >= 20 || hasLabel(item.issue, []string{"critical", "urgent", "p0"}) {
-			result["🔴 critical"] = append(result["🔴 critical"], issueInfo)
-		} else if item.score >= 10 || hasLabel(item.issue, []string{"high", "important", "p1"}) {
-			result["🟡 high"] = append(result["🟡 high"], issueInfo)
-		} else if item.score >= 5 || hasLabel(item.issue, []string{"medium", "p2"}) {
-			result["🟢 medium"] = append(result["🟢 medium"], issueInfo)
-		} else {
-			result["⚪ low"] = append(result["⚪ low"], issueInfo)
-		}
-	}
-
-	return result, nil
+	return scored, nil
 }
 
-func calculatePriorityScore(issue *github.Issue) int {
+func calculatePriorityScore(issue *github.Issue, now time.Time) int {
 	score := 0
 
 	// Comments weight (more discussion usually means more urgency or complexity)
@@ -278,7 +372,7 @@ func calculatePriorityScore(issue *github.Issue) int {
 
 	// Age weight (older unresolved issues might be more urgent to address)
 	created := issue.GetCreatedAt().Time
-	ageDays := int(time.Since(created).Hours() / 24)
+	ageDays := int(now.Sub(created).Hours() / 24)
 
 	if ageDays > 30 {
 		score += 5
@@ -324,4 +418,4 @@ func hasLabel(issue *github.Issue, keywords []string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -4,28 +4,43 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/go-github/v56/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools/githubtest"
 )
 
-func TestGetOpenIssues(t *testing.T) {
-	// Skip if no GitHub token is set
-	if os.Getenv("GITHUB_TOKEN") == "" {
-		t.Skip("GITHUB_TOKEN not set")
-	}
+// newRecordedService builds a Service authenticated with GITHUB_TOKEN, as
+// main does with the default configuration, talking to GitHub through the
+// test's cassette in testdata/cassettes. The cassette is replayed offline
+// when present and recorded with GITHUB_TOKEN when missing or when
+// GITHUB_RECORD=1 is set.
+func newRecordedService(t *testing.T) *Service {
+	t.Helper()
+	svc, err := NewService(Config{
+		Token:     os.Getenv("GITHUB_TOKEN"),
+		Transport: githubtest.NewCassette(t, filepath.Join("testdata", "cassettes", t.Name()+".json")),
+	})
+	require.NoError(t, err)
+	return svc
+}
 
+func TestGetOpenIssues(t *testing.T) {
 	// Create test input for a real repository
-	input := ToolInput{
-		Owner: "golang",
-		Repo:  "go",
-		State: "open",
+	input := ListIssuesInput{
+		RepoInput: RepoInput{Owner: "golang", Repo: "go"},
+		State:     "open",
 	}
 	rawInput, _ := json.Marshal(input)
 
-	// Test the function with real GitHub API
+	// Test the function with recorded GitHub API responses
 	ctx := context.Background()
-	issues, err := GetOpenIssues(ctx, rawInput)
+	issues, _, err := newRecordedService(t).GetOpenIssues(ctx, rawInput)
 
 	// Verify results
 	assert.NoError(t, err)
@@ -37,26 +52,21 @@ func TestGetOpenIssues(t *testing.T) {
 		assert.NotNil(t, issue.Title)
 		assert.NotNil(t, issue.State)
 		assert.Equal(t, "open", *issue.State)
+		assert.False(t, issue.IsPullRequest(), "pull requests are filtered out of issue listings")
 	}
 }
 
 func TestGetOpenPRs(t *testing.T) {
-	// Skip if no GitHub token is set
-	if os.Getenv("GITHUB_TOKEN") == "" {
-		t.Skip("GITHUB_TOKEN not set")
-	}
-
 	// Create test input for a real repository
-	input := ToolInput{
-		Owner: "golang",
-		Repo:  "go", 
-		State: "open",
+	input := ListPullRequestsInput{
+		RepoInput: RepoInput{Owner: "golang", Repo: "go"},
+		State:     "open",
 	}
 	rawInput, _ := json.Marshal(input)
 
-	// Test the function with real GitHub API
+	// Test the function with recorded GitHub API responses
 	ctx := context.Background()
-	prs, err := GetOpenPRs(ctx, rawInput)
+	prs, _, err := newRecordedService(t).GetOpenPRs(ctx, rawInput)
 
 	// Verify results
 	assert.NoError(t, err)
@@ -72,47 +82,280 @@ func TestGetOpenPRs(t *testing.T) {
 }
 
 func TestGetOpenIssuesInvalidJSON(t *testing.T) {
-    ctx := context.Background()
-    invalidJSON := json.RawMessage(`{"invalid": json}`)
-    
-    _, err := GetOpenIssues(ctx, invalidJSON)
-    assert.Error(t, err)
+	ctx := context.Background()
+	invalidJSON := json.RawMessage(`{"invalid": json}`)
+
+	svc, _ := newFakeService(t)
+	_, _, err := svc.GetOpenIssues(ctx, invalidJSON)
+	assert.Error(t, err)
 }
 
 func TestGetOpenIssuesInvalidRepo(t *testing.T) {
-    if os.Getenv("GITHUB_TOKEN") == "" {
-        t.Skip("GITHUB_TOKEN not set")
-    }
-    
-    input := ToolInput{
-        Owner: "nonexistent",
-        Repo:  "nonexistent-repo-12345",
-        State: "open",
-    }
-    rawInput, _ := json.Marshal(input)
-    
-    ctx := context.Background()
-    _, err := GetOpenIssues(ctx, rawInput)
-    assert.Error(t, err)
+	input := ListIssuesInput{
+		RepoInput: RepoInput{Owner: "nonexistent", Repo: "nonexistent-repo-12345"},
+		State:     "open",
+	}
+	rawInput, _ := json.Marshal(input)
+
+	ctx := context.Background()
+	_, _, err := newRecordedService(t).GetOpenIssues(ctx, rawInput)
+	assert.Error(t, err)
 }
 
 func TestGetOpenPRsDefaultState(t *testing.T) {
-    if os.Getenv("GITHUB_TOKEN") == "" {
-        t.Skip("GITHUB_TOKEN not set")
-    }
-    
-    input := ToolInput{
-        Owner: "golang",
-        Repo:  "go",
-        // State is empty, should default to "open"
-    }
-    rawInput, _ := json.Marshal(input)
-    
-    ctx := context.Background()
-    prs, err := GetOpenPRs(ctx, rawInput)
-    
-    assert.NoError(t, err)
-    for _, pr := range prs {
-        assert.Equal(t, "open", *pr.State)
-    }
-}
\ No newline at end of file
+	input := ListPullRequestsInput{
+		RepoInput: RepoInput{Owner: "golang", Repo: "go"},
+		// State is empty, should default to "open"
+	}
+	rawInput, _ := json.Marshal(input)
+
+	ctx := context.Background()
+	prs, _, err := newRecordedService(t).GetOpenPRs(ctx, rawInput)
+
+	assert.NoError(t, err)
+	for _, pr := range prs {
+		assert.Equal(t, "open", *pr.State)
+	}
+}
+
+// fakeNow is the fixed clock used by the offline tests
+var fakeNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeRepo returns the acme/widgets fixture shared by the offline tests
+func fakeRepo() *githubtest.Repo {
+	daysAgo := func(n int) *github.Timestamp {
+		return &github.Timestamp{Time: fakeNow.AddDate(0, 0, -n)}
+	}
+	return &githubtest.Repo{
+		Owner: "acme",
+		Name:  "widgets",
+		Issues: []*github.Issue{
+			{Number: github.Int(1), Title: github.String("Crash on startup"), State: github.String("open"),
+				Comments: github.Int(6), Reactions: &github.Reactions{TotalCount: github.Int(4)},
+				Labels: []*github.Label{{Name: github.String("bug")}}, CreatedAt: daysAgo(40)},
+			{Number: github.Int(2), Title: github.String("Docs typo"), State: github.String("open"),
+				CreatedAt: daysAgo(1)},
+			{Number: github.Int(3), Title: github.String("Add feature"), State: github.String("open"),
+				PullRequestLinks: &github.PullRequestLinks{URL: github.String("https://api.github.com/repos/acme/widgets/pulls/3")},
+				CreatedAt:        daysAgo(2)},
+			{Number: github.Int(4), Title: github.String("Security hole in auth"), State: github.String("open"),
+				Comments: github.Int(1), Labels: []*github.Label{{Name: github.String("p0")}}, CreatedAt: daysAgo(3)},
+			{Number: github.Int(5), Title: github.String("Old crash"), State: github.String("closed"),
+				CreatedAt: daysAgo(90)},
+		},
+		PullRequests: []*github.PullRequest{
+			{Number: github.Int(3), Title: github.String("Add feature"), State: github.String("open"), CreatedAt: daysAgo(2)},
+			{Number: github.Int(6), Title: github.String("Refactor"), State: github.String("open"), CreatedAt: daysAgo(5)},
+			{Number: github.Int(7), Title: github.String("WIP"), State: github.String("open"), Draft: github.Bool(true), CreatedAt: daysAgo(1)},
+			{Number: github.Int(8), Title: github.String("Merged"), State: github.String("closed"), CreatedAt: daysAgo(30)},
+		},
+		Reviews: map[int][]*github.PullRequestReview{
+			3: {{State: github.String("APPROVED")}},
+			6: {{State: github.String("COMMENTED")}},
+		},
+		Comments: map[int][]*github.IssueComment{
+			1: {
+				{User: &github.User{Login: github.String("alice")}, Body: github.String("Happens on every launch")},
+				{User: &github.User{Login: github.String("bob")}, Body: github.String("Confirmed on 2.1")},
+			},
+			3: {{User: &github.User{Login: github.String("carol")}, Body: github.String("Looks good")}},
+		},
+		Labels:    []*github.Label{{Name: github.String("bug")}, {Name: github.String("p0")}},
+		Assignees: []string{"octocat"},
+		Files: map[string]string{
+			"README.md":        "# Widgets\n",
+			"docs/guide.md":    "Read me first",
+			"docs/api/spec.md": "The API",
+		},
+		Refs: map[string]map[string]string{
+			"v1.0": {"README.md": "# Widgets 1.0\n"},
+		},
+	}
+}
+
+// newFakeService returns a Service talking to a fake GitHub seeded with fakeRepo
+func newFakeService(t *testing.T) (*Service, *githubtest.Server) {
+	t.Helper()
+	srv := githubtest.NewServer(fakeRepo())
+	t.Cleanup(srv.Close)
+
+	svc, err := NewService(Config{BaseURL: srv.URL, Now: func() time.Time { return fakeNow }})
+	require.NoError(t, err)
+	return svc, srv
+}
+
+func rawInput(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	return raw
+}
+
+func issueNumbers(issues []*github.Issue) []int {
+	var out []int
+	for _, issue := range issues {
+		out = append(out, issue.GetNumber())
+	}
+	return out
+}
+
+func prNumbers(prs []*github.PullRequest) []int {
+	var out []int
+	for _, pr := range prs {
+		out = append(out, pr.GetNumber())
+	}
+	return out
+}
+
+func TestFakeGetOpenIssues(t *testing.T) {
+	svc, _ := newFakeService(t)
+	ctx := context.Background()
+
+	issues, next, err := svc.GetOpenIssues(ctx, rawInput(t, ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 4}, issueNumbers(issues))
+	assert.Empty(t, next)
+
+	issues, _, err = svc.GetOpenIssues(ctx, rawInput(t, ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, State: "closed"}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, issueNumbers(issues))
+}
+
+func TestFakeGetOpenIssuesWalksPages(t *testing.T) {
+	svc, _ := newFakeService(t)
+	ctx := context.Background()
+
+	input := ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, PageInput: PageInput{PerPage: 2, MaxResults: 2}}
+	issues, next, err := svc.GetOpenIssues(ctx, rawInput(t, input))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, issueNumbers(issues))
+	require.NotEmpty(t, next)
+
+	input.PageInput = PageInput{Cursor: next, MaxResults: 2}
+	issues, next, err = svc.GetOpenIssues(ctx, rawInput(t, input))
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, issueNumbers(issues))
+	assert.Empty(t, next)
+}
+
+func TestFakeGetOpenIssuesUnknownRepo(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	_, _, err := svc.GetOpenIssues(context.Background(), rawInput(t, ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "missing"}}))
+	assert.Error(t, err)
+}
+
+func TestFakeGetOpenPRs(t *testing.T) {
+	svc, _ := newFakeService(t)
+	ctx := context.Background()
+
+	prs, _, err := svc.GetOpenPRs(ctx, rawInput(t, ListPullRequestsInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 6, 7}, prNumbers(prs))
+
+	prs, _, err = svc.GetOpenPRs(ctx, rawInput(t, ListPullRequestsInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, State: "all"}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 6, 7, 8}, prNumbers(prs))
+}
+
+func TestFakeSearchIssues(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	issues, _, err := svc.SearchIssues(context.Background(), rawInput(t, SearchIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Query: "crash"}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, issueNumbers(issues))
+}
+
+func TestFakeGetPendingReviews(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	prs, err := svc.GetPendingReviews(context.Background(), rawInput(t, PendingReviewsInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{6, 7}, prNumbers(prs))
+}
+
+func TestFakeCreateIssue(t *testing.T) {
+	svc, srv := newFakeService(t)
+
+	issue, err := svc.CreateIssue(context.Background(), rawInput(t, CreateIssueInput{
+		RepoInput: RepoInput{Owner: "acme", Repo: "widgets"},
+		Title:     "Flaky test",
+		Body:      "Fails one run in ten",
+		Labels:    []string{"bug", "ci"},
+		Assignee:  "octocat",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 9, issue.GetNumber())
+	assert.Equal(t, "open", issue.GetState())
+	assert.Equal(t, "https://github.com/acme/widgets/issues/9", issue.GetHTMLURL())
+
+	repo := srv.Repo("acme", "widgets")
+	created := repo.Issues[len(repo.Issues)-1]
+	assert.Equal(t, "Flaky test", created.GetTitle())
+	assert.Equal(t, "Fails one run in ten", created.GetBody())
+	assert.Len(t, created.Labels, 2)
+	assert.Equal(t, "octocat", created.GetAssignee().GetLogin())
+}
+
+func TestFakePreviewIssue(t *testing.T) {
+	svc, srv := newFakeService(t)
+	ctx := context.Background()
+
+	preview, err := svc.PreviewIssue(ctx, rawInput(t, CreateIssueInput{
+		RepoInput: RepoInput{Owner: "acme", Repo: "widgets"},
+		Title:     "Flaky test",
+		Labels:    []string{"Bug", " ci ", ""},
+		Assignee:  "ghost",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "Flaky test", preview.Request.GetTitle())
+	assert.Equal(t, []string{"Bug", "ci"}, preview.Request.GetLabels())
+	assert.Equal(t, "ghost", preview.Request.GetAssignee())
+	require.Len(t, preview.Warnings, 2)
+	assert.Contains(t, preview.Warnings[0], `label "ci" does not exist in acme/widgets`)
+	assert.Contains(t, preview.Warnings[1], "ghost cannot be assigned issues in acme/widgets")
+
+	// Nothing was created
+	assert.Len(t, srv.Repo("acme", "widgets").Issues, 5)
+	for _, req := range srv.Requests {
+		assert.Regexp(t, "^GET ", req)
+	}
+
+	preview, err = svc.PreviewIssue(ctx, rawInput(t, CreateIssueInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Title: "Ok", Labels: []string{"bug"}, Assignee: "octocat"}))
+	require.NoError(t, err)
+	assert.Empty(t, preview.Warnings)
+}
+
+func TestFakePreviewIssueValidates(t *testing.T) {
+	svc, _ := newFakeService(t)
+	ctx := context.Background()
+
+	_, err := svc.PreviewIssue(ctx, rawInput(t, CreateIssueInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Title: "  "}))
+	assert.EqualError(t, err, "invalid arguments: title must not be empty")
+
+	_, err = svc.PreviewIssue(ctx, rawInput(t, CreateIssueInput{RepoInput: RepoInput{Owner: "acme", Repo: "missing"}, Title: "Flaky test"}))
+	assert.Error(t, err)
+}
+
+func TestFakeAnalyzeIssuePriority(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	analysis, err := svc.AnalyzeIssuePriority(context.Background(), rawInput(t, PriorityInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+
+	numbers := func(category string) []int {
+		var out []int
+		for _, scored := range analysis {
+			if scored.Category == category {
+				out = append(out, scored.Issue.GetNumber())
+			}
+		}
+		return out
+	}
+	assert.Equal(t, []int{1, 4}, numbers(PriorityCritical))
+	assert.Empty(t, numbers(PriorityHigh))
+	assert.Empty(t, numbers(PriorityMedium))
+	assert.Equal(t, []int{2}, numbers(PriorityLow))
+	assert.Equal(t, 1, analysis[0].Issue.GetNumber())
+	assert.Equal(t, 26, analysis[0].Score)
+}
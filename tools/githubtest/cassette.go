@@ -0,0 +1,29 @@
+package githubtest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/himanshusharma89/github-mcp-server/tools/recorder"
+)
+
+// NewCassette returns a transport for the cassette at path for use in a test.
+// It replays the cassette when it exists, and records a new one against real
+// GitHub when it does not or GITHUB_RECORD=1 is set. Recording requires
+// GITHUB_TOKEN; without it the test is skipped.
+func NewCassette(t testing.TB, path string) *recorder.Recorder {
+	t.Helper()
+	mode := recorder.ModeReplay
+	if _, err := os.Stat(path); err != nil || os.Getenv("GITHUB_RECORD") == "1" {
+		if os.Getenv("GITHUB_TOKEN") == "" {
+			t.Skipf("no cassette at %s; set GITHUB_TOKEN to record one", path)
+		}
+		mode = recorder.ModeRecord
+	}
+
+	r, err := recorder.NewRecorder(path, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
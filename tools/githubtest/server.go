@@ -0,0 +1,665 @@
+// Package githubtest provides an in-process fake of the GitHub REST API
+// endpoints used by the tools package, for hermetic tests.
+package githubtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Repo is the seeded state of one fake repository
+type Repo struct {
+	Owner        string
+	Name         string
+	Issues       []*github.Issue
+	PullRequests []*github.PullRequest
+	// Reviews holds the reviews of each pull request, keyed by PR number
+	Reviews map[int][]*github.PullRequestReview
+	// Comments holds the comments on each issue and pull request, keyed by number
+	Comments map[int][]*github.IssueComment
+	// Labels defined in the repository
+	Labels []*github.Label
+	// Assignees are the logins that can be assigned issues
+	Assignees []string
+	// Files maps the paths of files on the default branch to their contents.
+	// Directories are implied by the paths.
+	Files map[string]string
+	// Refs holds the files of other branches, tags and commits, keyed by ref
+	Refs map[string]map[string]string
+}
+
+// Server is a fake GitHub API serving issues, comments, labels, assignees, pulls, reviews, contents and search for
+// seeded repos, and the users, organizations and repository listings behind them
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	repos map[string]*Repo
+	users map[string]string
+	// orgs lists the organizations each login belongs to
+	orgs map[string][]string
+	// Requests records the method and path of every request received, in order
+	Requests []string
+}
+
+// NewServer starts a fake GitHub API seeded with repos. Point a client at it
+// with tools.Config{BaseURL: srv.URL} and Close it when done.
+func NewServer(repos ...*Repo) *Server {
+	s := &Server{repos: make(map[string]*Repo), users: make(map[string]string), orgs: make(map[string][]string)}
+	for _, r := range repos {
+		s.AddRepo(r)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.getRepo)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues", s.listIssues)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}", s.getIssue)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/issues/{number}/comments", s.listComments)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/labels", s.listLabels)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/assignees", s.listAssignees)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/assignees/{assignee}", s.checkAssignee)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls", s.listPulls)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}", s.getPull)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/pulls/{number}/reviews", s.listReviews)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.getContents)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/readme", s.getReadme)
+	mux.HandleFunc("GET /search/issues", s.searchIssues)
+	mux.HandleFunc("GET /user", s.getUser)
+	mux.HandleFunc("GET /user/orgs", s.listUserOrgs)
+	mux.HandleFunc("GET /user/repos", s.listUserRepos)
+	mux.HandleFunc("GET /users/{user}/repos", s.listOwnerRepos)
+	mux.HandleFunc("GET /orgs/{org}/repos", s.listOrgRepos)
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// AddRepo seeds a repository, replacing any existing one with the same name
+func (s *Server) AddRepo(r *Repo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Reviews == nil {
+		r.Reviews = make(map[int][]*github.PullRequestReview)
+	}
+	if r.Comments == nil {
+		r.Comments = make(map[int][]*github.IssueComment)
+	}
+	s.repos[repoKey(r.Owner, r.Name)] = r
+}
+
+// AddUser makes GET /user answer login for requests authenticated with token
+func (s *Server) AddUser(token, login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[token] = login
+}
+
+// AddOrgMember makes org an organization that login belongs to
+func (s *Server) AddOrgMember(org, login string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs[login] = append(s.orgs[login], org)
+}
+
+// Repo returns the current state of a seeded repository, including created issues
+func (s *Server) Repo(owner, name string) *Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.repos[repoKey(owner, name)]
+}
+
+// nextNumber returns the number the next issue or pull request in r receives
+func (r *Repo) nextNumber() int {
+	max := 0
+	for _, issue := range r.Issues {
+		if issue.GetNumber() > max {
+			max = issue.GetNumber()
+		}
+	}
+	for _, pr := range r.PullRequests {
+		if pr.GetNumber() > max {
+			max = pr.GetNumber()
+		}
+	}
+	return max + 1
+}
+
+func repoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// repo looks up the repository named in the request path, answering 404 when unknown
+func (s *Server) repo(w http.ResponseWriter, r *http.Request) *Repo {
+	repo, ok := s.repos[repoKey(r.PathValue("owner"), r.PathValue("repo"))]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return nil
+	}
+	return repo
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &github.Repository{
+		Name:      github.String(repo.Name),
+		FullName:  github.String(repo.Owner + "/" + repo.Name),
+		Owner:     &github.User{Login: github.String(repo.Owner)},
+		HasIssues: github.Bool(true),
+	})
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	var out []*github.Issue
+	for _, issue := range repo.Issues {
+		if matchState(r.URL.Query().Get("state"), issue.GetState()) {
+			out = append(out, issue)
+		}
+	}
+	writePage(w, r, out)
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	var req github.IssueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if req.GetTitle() == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	number := repo.nextNumber()
+	now := github.Timestamp{Time: time.Now().UTC().Truncate(time.Second)}
+	issue := &github.Issue{
+		Number:    github.Int(number),
+		Title:     req.Title,
+		Body:      req.Body,
+		State:     github.String("open"),
+		HTMLURL:   github.String(fmt.Sprintf("https://github.com/%s/%s/issues/%d", repo.Owner, repo.Name, number)),
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+	if req.Labels != nil {
+		for _, name := range *req.Labels {
+			issue.Labels = append(issue.Labels, &github.Label{Name: github.String(name)})
+		}
+	}
+	if req.Assignee != nil {
+		issue.Assignee = &github.User{Login: req.Assignee}
+	}
+	repo.Issues = append(repo.Issues, issue)
+
+	writeJSON(w, http.StatusCreated, issue)
+}
+
+func (s *Server) getIssue(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	for _, issue := range repo.Issues {
+		if issue.GetNumber() == number {
+			writeJSON(w, http.StatusOK, issue)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	writePage(w, r, repo.Comments[number])
+}
+
+func (s *Server) listLabels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	writePage(w, r, repo.Labels)
+}
+
+func (s *Server) listAssignees(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	var out []*github.User
+	for _, login := range repo.Assignees {
+		out = append(out, &github.User{Login: github.String(login)})
+	}
+	writePage(w, r, out)
+}
+
+// checkAssignee answers 204 when the login can be assigned issues and 404 otherwise
+func (s *Server) checkAssignee(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	for _, login := range repo.Assignees {
+		if strings.EqualFold(login, r.PathValue("assignee")) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listPulls(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	var out []*github.PullRequest
+	for _, pr := range repo.PullRequests {
+		if matchState(r.URL.Query().Get("state"), pr.GetState()) {
+			out = append(out, pr)
+		}
+	}
+	writePage(w, r, out)
+}
+
+func (s *Server) getPull(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	for _, pr := range repo.PullRequests {
+		if pr.GetNumber() == number {
+			writeJSON(w, http.StatusOK, pr)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) listReviews(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	writePage(w, r, repo.Reviews[number])
+}
+
+// maxInlineSize is the largest file the contents API returns inline
+const maxInlineSize = 1 << 20
+
+// files returns the files of repo at the request's ref, answering 404 when the ref is unknown
+func (repo *Repo) files(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	ref := r.URL.Query().Get("ref")
+	if ref == "" || ref == "main" {
+		return repo.Files, true
+	}
+	files, ok := repo.Refs[ref]
+	if !ok {
+		writeError(w, http.StatusNotFound, "No commit found for the ref "+ref)
+	}
+	return files, ok
+}
+
+// getContents answers with the file at the path, or the entries of the directory there
+func (s *Server) getContents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+	files, ok := repo.files(w, r)
+	if !ok {
+		return
+	}
+
+	dir := strings.Trim(r.PathValue("path"), "/")
+	if content, ok := files[dir]; ok {
+		writeJSON(w, http.StatusOK, fileContent(dir, content))
+		return
+	}
+
+	// Collect the direct children of dir, files and subdirectories alike
+	prefix := dir + "/"
+	if dir == "" {
+		prefix = ""
+	}
+	entries := make(map[string]*github.RepositoryContent)
+	for path, content := range files {
+		rest, ok := strings.CutPrefix(path, prefix)
+		if !ok {
+			continue
+		}
+		if name, _, isDir := strings.Cut(rest, "/"); isDir {
+			entries[name] = &github.RepositoryContent{Type: github.String("dir"), Name: github.String(name),
+				Path: github.String(prefix + name), Size: github.Int(0)}
+		} else {
+			entry := fileContent(path, content)
+			entry.Content, entry.Encoding = nil, nil
+			entries[name] = entry
+		}
+	}
+	if len(entries) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	list := make([]*github.RepositoryContent, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].GetName() < list[j].GetName() })
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getReadme answers with the README file at the root of the repository
+func (s *Server) getReadme(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := s.repo(w, r)
+	if repo == nil {
+		return
+	}
+	files, ok := repo.files(w, r)
+	if !ok {
+		return
+	}
+
+	for path, content := range files {
+		if !strings.Contains(path, "/") && strings.HasPrefix(strings.ToLower(path), "readme") {
+			writeJSON(w, http.StatusOK, fileContent(path, content))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// fileContent describes a file the way the contents API does, inlining it
+// base64 encoded unless it is too large
+func fileContent(path, content string) *github.RepositoryContent {
+	name := path[strings.LastIndex(path, "/")+1:]
+	file := &github.RepositoryContent{
+		Type:     github.String("file"),
+		Name:     github.String(name),
+		Path:     github.String(path),
+		Size:     github.Int(len(content)),
+		Encoding: github.String("none"),
+		Content:  github.String(""),
+	}
+	if len(content) <= maxInlineSize {
+		file.Encoding = github.String("base64")
+		file.Content = github.String(base64.StdEncoding.EncodeToString([]byte(content)))
+	}
+	return file
+}
+
+// getUser answers with the user registered for the request's token, see AddUser
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login := s.login(w, r)
+	if login == "" {
+		return
+	}
+	writeJSON(w, http.StatusOK, &github.User{Login: github.String(login)})
+}
+
+func (s *Server) listUserOrgs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login := s.login(w, r)
+	if login == "" {
+		return
+	}
+	var out []*github.Organization
+	for _, org := range s.orgs[login] {
+		out = append(out, &github.Organization{Login: github.String(org)})
+	}
+	writePage(w, r, out)
+}
+
+// listUserRepos lists the repositories owned by the authenticated user
+func (s *Server) listUserRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	login := s.login(w, r)
+	if login == "" {
+		return
+	}
+	writePage(w, r, s.reposOf(login))
+}
+
+func (s *Server) listOwnerRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writePage(w, r, s.reposOf(r.PathValue("user")))
+}
+
+// listOrgRepos answers 404 for owners no login is a member of, as GitHub does for users
+func (s *Server) listOrgRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org := r.PathValue("org")
+	for _, orgs := range s.orgs {
+		for _, name := range orgs {
+			if strings.EqualFold(name, org) {
+				writePage(w, r, s.reposOf(org))
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "Not Found")
+}
+
+// reposOf returns the seeded repositories of owner, sorted by name
+func (s *Server) reposOf(owner string) []*github.Repository {
+	var out []*github.Repository
+	for _, repo := range s.repos {
+		if strings.EqualFold(repo.Owner, owner) {
+			out = append(out, &github.Repository{
+				Name:     github.String(repo.Name),
+				FullName: github.String(repo.Owner + "/" + repo.Name),
+				Owner:    &github.User{Login: github.String(repo.Owner)},
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].GetName() < out[j].GetName() })
+	return out
+}
+
+// login returns the user authenticated by the request's token, answering 401 when there is none
+func (s *Server) login(w http.ResponseWriter, r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(strings.TrimPrefix(auth, "Bearer "), "token ")
+	login, ok := s.users[token]
+	if auth == "" || !ok {
+		writeError(w, http.StatusUnauthorized, "Requires authentication")
+		return ""
+	}
+	return login
+}
+
+// searchIssues understands the repo:, type:, state: and is: qualifiers and
+// matches the remaining terms against issue titles and bodies
+func (s *Server) searchIssues(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var repoName, state string
+	var terms []string
+	for _, field := range strings.Fields(r.URL.Query().Get("q")) {
+		key, value, ok := strings.Cut(field, ":")
+		switch {
+		case ok && key == "repo":
+			repoName = value
+		case ok && (key == "state" || key == "is") && (value == "open" || value == "closed"):
+			state = value
+		case ok && key == "type":
+		default:
+			terms = append(terms, strings.ToLower(field))
+		}
+	}
+
+	repo, ok := s.repos[strings.ToLower(repoName)]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	var matches []*github.Issue
+	for _, issue := range repo.Issues {
+		if issue.IsPullRequest() || !matchState(state, issue.GetState()) {
+			continue
+		}
+		text := strings.ToLower(issue.GetTitle() + " " + issue.GetBody())
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, issue)
+		}
+	}
+
+	page := paginate(w, r, matches)
+	writeJSON(w, http.StatusOK, &github.IssuesSearchResult{
+		Total:             github.Int(len(matches)),
+		IncompleteResults: github.Bool(false),
+		Issues:            page,
+	})
+}
+
+// matchState reports whether an item in state is listed for the requested state filter
+func matchState(filter, state string) bool {
+	return filter == "" || filter == "all" || filter == state
+}
+
+// writePage writes the requested page of items with a GitHub style Link header
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page := paginate(w, r, items)
+	if page == nil {
+		page = []T{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// paginate slices items by the page and per_page query parameters and sets
+// the Link header pointing at the next page when there is one
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return nil
+	}
+	end := start + perPage
+	if end < len(items) {
+		next := *r.URL
+		nq := url.Values{}
+		for k, v := range q {
+			nq[k] = v
+		}
+		nq.Set("page", strconv.Itoa(page+1))
+		next.RawQuery = nq.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+	} else {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
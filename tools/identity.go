@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Identity describes who tool calls for owner act as on GitHub: the login
+// behind the caller's or the server's token, "app:<id>" when a GitHub App
+// installation is used, or "anonymous". Logins are looked up once per token.
+func (s *Service) Identity(ctx context.Context, owner string) string {
+	ctx, client := s.clientFor(ctx, owner)
+
+	token, ok := TokenFromContext(ctx)
+	if !ok {
+		if s.config.App != nil {
+			return fmt.Sprintf("app:%d", s.config.App.AppID)
+		}
+		token = s.config.Token
+		if client == s.enterprise {
+			token = s.config.enterpriseToken()
+		}
+	}
+	if token == "" {
+		return "anonymous"
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	key := client.BaseURL.String() + " " + hex.EncodeToString(sum[:])
+	if login, ok := s.logins.Load(key); ok {
+		return login.(string)
+	}
+
+	// Look the login up with exactly this token, whatever the fallback rules
+	login := "token:" + hex.EncodeToString(sum[:4])
+	if user, _, err := client.Users.Get(WithToken(ctx, token), ""); err == nil && user.GetLogin() != "" {
+		login = user.GetLogin()
+	}
+	s.logins.Store(key, login)
+	return login
+}
@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools/githubtest"
+)
+
+func TestIdentity(t *testing.T) {
+	srv := githubtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddUser("server-token", "mcp-bot")
+	srv.AddUser("caller-token", "octocat")
+
+	svc, err := NewService(Config{BaseURL: srv.URL, Token: "server-token"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.Equal(t, "mcp-bot", svc.Identity(ctx, "acme"))
+	assert.Equal(t, "octocat", svc.Identity(WithToken(ctx, "caller-token"), "acme"))
+	assert.Equal(t, "anonymous", svc.Identity(WithToken(ctx, ""), "acme"))
+	assert.Regexp(t, "^token:[0-9a-f]{8}$", svc.Identity(WithToken(ctx, "unknown-token"), "acme"))
+
+	// Logins are remembered per token
+	requests := len(srv.Requests)
+	assert.Equal(t, "mcp-bot", svc.Identity(ctx, "acme"))
+	assert.Len(t, srv.Requests, requests)
+}
+
+func TestIdentityForApp(t *testing.T) {
+	svc, err := NewService(Config{App: newTestApp(t)})
+	require.NoError(t, err)
+	assert.Equal(t, "app:42", svc.Identity(context.Background(), "acme"))
+}
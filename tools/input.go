@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Each tool decodes its arguments into its own input type. The struct tags
+// are the single definition of the tool's arguments: InputSchema reflects
+// them into the JSON schema advertised to clients, and DecodeInput validates
+// calls against that same schema before any GitHub request is made.
+
+// RepoInput names the repository a tool works on
+type RepoInput struct {
+	Owner string `json:"owner" jsonschema:"required,minLength=1" jsonschema_description:"GitHub org or user"`
+	Repo  string `json:"repo" jsonschema:"required,minLength=1" jsonschema_description:"GitHub repository name"`
+}
+
+// ListIssuesInput is the input of list_issues
+type ListIssuesInput struct {
+	RepoInput
+	State string `json:"state,omitempty" jsonschema:"enum=open,enum=closed,enum=all" jsonschema_description:"State of issues to list. Defaults to open"`
+	PageInput
+}
+
+// ListPullRequestsInput is the input of list_prs
+type ListPullRequestsInput struct {
+	RepoInput
+	State string `json:"state,omitempty" jsonschema:"enum=open,enum=closed,enum=all" jsonschema_description:"State of PRs to list. Defaults to open"`
+	PageInput
+}
+
+// SearchIssuesInput is the input of search_issues
+type SearchIssuesInput struct {
+	RepoInput
+	Query      string `json:"query" jsonschema:"required,minLength=1" jsonschema_description:"Search query/topic to filter issues"`
+	State      string `json:"state,omitempty" jsonschema:"enum=open,enum=closed,enum=all" jsonschema_description:"State of issues to search. Defaults to open"`
+	Prioritize bool   `json:"prioritize,omitempty" jsonschema_description:"Whether to sort by comments plus reactions and group into high (10 or more), medium (3 or more) and low priority. Defaults to false"`
+	PageInput
+}
+
+// PendingReviewsInput is the input of get_pending_reviews
+type PendingReviewsInput struct {
+	RepoInput
+}
+
+// CreateIssueInput is the input of create_issue
+type CreateIssueInput struct {
+	RepoInput
+	Title    string   `json:"title" jsonschema:"required,minLength=1" jsonschema_description:"Issue title"`
+	Body     string   `json:"body,omitempty" jsonschema_description:"Issue body/description"`
+	Labels   []string `json:"labels,omitempty" jsonschema_description:"Labels to apply to the issue"`
+	Assignee string   `json:"assignee,omitempty" jsonschema_description:"Username to assign the issue to"`
+	DryRun   *bool    `json:"dry_run,omitempty" jsonschema_description:"Validate the input and return the request that would be sent without creating the issue. Defaults to the server's --dry-run setting"`
+}
+
+// PriorityInput is the input of analyze_issue_priority
+type PriorityInput struct {
+	RepoInput
+	Limit int `json:"limit,omitempty" jsonschema:"minimum=1,maximum=100" jsonschema_description:"Maximum number of issues to analyze. Defaults to 20"`
+}
+
+// inputSchemas caches the schema reflected from each input type
+var inputSchemas sync.Map
+
+// schemaOf returns the JSON schema of the input type t
+func schemaOf(t reflect.Type) *jsonschema.Schema {
+	if schema, ok := inputSchemas.Load(t); ok {
+		return schema.(*jsonschema.Schema)
+	}
+	reflector := jsonschema.Reflector{
+		DoNotReference:             true,
+		Anonymous:                  true,
+		RequiredFromJSONSchemaTags: true,
+	}
+	schema := reflector.ReflectFromType(t)
+	schema.Version = ""
+	inputSchemas.Store(t, schema)
+	return schema
+}
+
+// InputSchema returns the JSON schema of the input type T, for advertising a tool's arguments
+func InputSchema[T any]() json.RawMessage {
+	raw, err := json.Marshal(schemaOf(reflect.TypeOf((*T)(nil)).Elem()))
+	if err != nil {
+		panic(fmt.Sprintf("input schema of %T: %v", *new(T), err))
+	}
+	return raw
+}
+
+// DecodeInput validates input against the schema of T and decodes it. Values
+// in a commonly confused shape, such as numbers sent as strings, are coerced
+// to the declared type first. Every problem left, including unknown
+// arguments, is reported in one InputError.
+func DecodeInput[T any](input json.RawMessage) (T, error) {
+	var params T
+	schema := schemaOf(reflect.TypeOf(params))
+
+	var args map[string]interface{}
+	if trimmed := bytes.TrimSpace(input); len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(input, &args); err != nil {
+			return params, invalidInput("invalid arguments: arguments must be a JSON object: %v", err)
+		}
+	}
+	coerceInput(schema, args)
+	if problems := validateInput(schema, args); len(problems) > 0 {
+		return params, invalidInput("invalid arguments: %s", strings.Join(problems, "; "))
+	}
+
+	if len(args) > 0 {
+		normalized, err := json.Marshal(args)
+		if err == nil {
+			err = json.Unmarshal(normalized, &params)
+		}
+		if err != nil {
+			return params, invalidInput("invalid arguments: %v", err)
+		}
+	}
+	return params, nil
+}
+
+// validateInput checks args against schema and describes every problem
+func validateInput(schema *jsonschema.Schema, args map[string]interface{}) []string {
+	var problems []string
+
+	var names []string
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop, ok := schema.Properties.Get(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown argument %q (expected %s)", name, strings.Join(propertyNames(schema), ", ")))
+			continue
+		}
+		if args[name] != nil {
+			problems = append(problems, validateValue(name, prop, args[name])...)
+		}
+	}
+
+	for _, name := range schema.Required {
+		if args[name] == nil {
+			problems = append(problems, fmt.Sprintf("missing required argument %q", name))
+		}
+	}
+	return problems
+}
+
+// validateValue checks one argument value against its property schema
+func validateValue(name string, prop *jsonschema.Schema, value interface{}) []string {
+	switch prop.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string, got %s", name, jsonType(value))}
+		}
+		if prop.MinLength != nil && uint64(len(strings.TrimSpace(s))) < *prop.MinLength {
+			return []string{fmt.Sprintf("%s must not be empty", name)}
+		}
+		if len(prop.Enum) > 0 && !inEnum(prop.Enum, s) {
+			var allowed []string
+			for _, v := range prop.Enum {
+				allowed = append(allowed, fmt.Sprint(v))
+			}
+			return []string{fmt.Sprintf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), s)}
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok || (prop.Type == "integer" && n != math.Trunc(n)) {
+			return []string{fmt.Sprintf("%s must be %s, got %s", name, article(prop.Type), describe(value))}
+		}
+		if min, err := prop.Minimum.Float64(); err == nil && n < min {
+			return []string{fmt.Sprintf("%s must be at least %s, got %v", name, prop.Minimum, n)}
+		}
+		if max, err := prop.Maximum.Float64(); err == nil && n > max {
+			return []string{fmt.Sprintf("%s must be at most %s, got %v", name, prop.Maximum, n)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be a boolean, got %s", name, describe(value))}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array, got %s", name, jsonType(value))}
+		}
+		var problems []string
+		if prop.Items != nil {
+			for i, item := range items {
+				problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", name, i), prop.Items, item)...)
+			}
+		}
+		return problems
+	}
+	return nil
+}
+
+// propertyNames lists the arguments schema accepts, in declaration order
+func propertyNames(schema *jsonschema.Schema) []string {
+	var names []string
+	for pair := schema.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		names = append(names, pair.Key)
+	}
+	return names
+}
+
+func inEnum(enum []interface{}, s string) bool {
+	for _, v := range enum {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// article prefixes a JSON schema type name with its indefinite article
+func article(typ string) string {
+	if typ == "integer" {
+		return "an integer"
+	}
+	return "a " + typ
+}
+
+// describe shows a scalar value, or names the JSON type of anything else
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%v", v)
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+	return jsonType(value)
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case []interface{}:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
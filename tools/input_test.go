@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputSchema(t *testing.T) {
+	var schema struct {
+		Type                 string                    `json:"type"`
+		Properties           map[string]map[string]any `json:"properties"`
+		Required             []string                  `json:"required"`
+		AdditionalProperties bool                      `json:"additionalProperties"`
+	}
+	require.NoError(t, json.Unmarshal(InputSchema[ListIssuesInput](), &schema))
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"owner", "repo"}, schema.Required)
+	assert.False(t, schema.AdditionalProperties)
+	assert.Equal(t, []any{"open", "closed", "all"}, schema.Properties["state"]["enum"])
+	assert.Equal(t, "integer", schema.Properties["per_page"]["type"])
+	assert.EqualValues(t, 100, schema.Properties["per_page"]["maximum"])
+	assert.Equal(t, "GitHub org or user", schema.Properties["owner"]["description"])
+}
+
+func TestDecodeInput(t *testing.T) {
+	params, err := DecodeInput[SearchIssuesInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "query": "crash", "state": "all", "per_page": 10, "prioritize": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, SearchIssuesInput{
+		RepoInput:  RepoInput{Owner: "acme", Repo: "widgets"},
+		Query:      "crash",
+		State:      "all",
+		Prioritize: true,
+		PageInput:  PageInput{PerPage: 10},
+	}, params)
+}
+
+func TestDecodeInputRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name, input, want string
+	}{
+		{"missing required", `{"owner": "acme"}`,
+			`invalid arguments: missing required argument "repo"`},
+		{"empty", `null`,
+			`invalid arguments: missing required argument "owner"; missing required argument "repo"`},
+		{"blank string", `{"owner": "acme", "repo": " "}`,
+			`invalid arguments: repo must not be empty`},
+		{"unknown argument", `{"owner": "acme", "repo": "widgets", "stat": "open"}`,
+			`invalid arguments: unknown argument "stat" (expected owner, repo, state, page, per_page, max_results, cursor)`},
+		{"enum", `{"owner": "acme", "repo": "widgets", "state": "opne"}`,
+			`invalid arguments: state must be one of open, closed, all, got "opne"`},
+		{"minimum", `{"owner": "acme", "repo": "widgets", "page": -1}`,
+			`invalid arguments: page must be at least 1, got -1`},
+		{"maximum", `{"owner": "acme", "repo": "widgets", "per_page": 500}`,
+			`invalid arguments: per_page must be at most 100, got 500`},
+		{"integer", `{"owner": "acme", "repo": "widgets", "max_results": 2.5}`,
+			`invalid arguments: max_results must be an integer, got 2.5`},
+		{"type", `{"owner": {"login": "acme"}, "repo": "widgets", "page": "two"}`,
+			`invalid arguments: owner must be a string, got an object; page must be an integer, got "two"`},
+		{"not an object", `[1]`,
+			`invalid arguments: arguments must be a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := DecodeInput[ListIssuesInput](json.RawMessage(tc.input))
+			var inputErr *InputError
+			require.ErrorAs(t, err, &inputErr)
+			assert.EqualError(t, err, tc.want)
+		})
+	}
+}
+
+func TestDecodeInputArrays(t *testing.T) {
+	_, err := DecodeInput[CreateIssueInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "title": "Bug", "labels": ["bug", {"name": "ci"}]}`))
+	assert.EqualError(t, err, "invalid arguments: labels[1] must be a string, got an object")
+
+	params, err := DecodeInput[CreateIssueInput](json.RawMessage(`{"owner": "acme", "repo": "widgets", "title": "Bug", "labels": ["bug"], "dry_run": false}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bug"}, params.Labels)
+	require.NotNil(t, params.DryRun)
+	assert.False(t, *params.DryRun)
+}
+
+func TestServiceValidatesBeforeCallingGitHub(t *testing.T) {
+	svc, srv := newFakeService(t)
+
+	_, err := svc.AnalyzeIssuePriority(context.Background(), json.RawMessage(`{"owner": "acme", "repo": "widgets", "limit": -5}`))
+	assert.EqualError(t, err, "invalid arguments: limit must be at least 1, got -5")
+	assert.Empty(t, srv.Requests)
+}
@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// maxPerPage is the largest page size the GitHub REST API accepts
+const maxPerPage = 100
+
+// PageInput holds the pagination arguments shared by listing tools
+type PageInput struct {
+	Page       int    `json:"page,omitempty" jsonschema:"minimum=1" jsonschema_description:"Page number to start from. Defaults to 1"`
+	PerPage    int    `json:"per_page,omitempty" jsonschema:"minimum=1,maximum=100" jsonschema_description:"Results per page, at most 100. Defaults to the server's configured page size"`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"minimum=1" jsonschema_description:"Maximum results to return, following further pages as needed. Defaults to one page"`
+	Cursor     string `json:"cursor,omitempty" jsonschema_description:"next_cursor from a previous call, to continue where it stopped. Overrides page and per_page"`
+}
+
+// pageCursor is the position a listing stopped at. It is handed to the caller
+// as an opaque string so the next call resumes exactly where this one ended.
+type pageCursor struct {
+	Page    int `json:"p"`
+	PerPage int `json:"n"`
+	Offset  int `json:"o,omitempty"`
+}
+
+func (c pageCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.Page < 1 || c.PerPage < 1 || c.PerPage > maxPerPage || c.Offset < 0 {
+		return pageCursor{}, invalidInput("invalid cursor: pass the next_cursor returned by a previous call unchanged")
+	}
+	return c, nil
+}
+
+// start resolves where a listing begins and how many results it may return.
+// A cursor takes precedence over page and per_page. max_results defaults to
+// one page; larger values follow the next pages until the limit is reached.
+func (in PageInput) start() (pageCursor, int, error) {
+	if in.Page < 0 || in.PerPage < 0 || in.MaxResults < 0 {
+		return pageCursor{}, 0, invalidInput("page, per_page and max_results must not be negative")
+	}
+	if in.PerPage > maxPerPage {
+		return pageCursor{}, 0, invalidInput("per_page must be at most %d", maxPerPage)
+	}
+
+	c := pageCursor{Page: in.Page, PerPage: in.PerPage}
+	if in.Cursor != "" {
+		var err error
+		if c, err = decodeCursor(in.Cursor); err != nil {
+			return pageCursor{}, 0, err
+		}
+	}
+	if c.Page == 0 {
+		c.Page = 1
+	}
+	if c.PerPage == 0 {
+		c.PerPage = maxPerPage
+	}
+
+	limit := in.MaxResults
+	if limit == 0 {
+		limit = c.PerPage
+	}
+	return c, limit, nil
+}
+
+// paginate collects up to in.MaxResults items that satisfy keep, fetching
+// pages with fetch and following Response.NextPage. It returns the items and
+// a cursor for the next call, or "" once the listing is exhausted. Moving on
+// to another page reports progress as the number of items, named by noun,
+// collected so far.
+func paginate[T any](ctx context.Context, in PageInput, noun string, fetch func(opts github.ListOptions) ([]T, *github.Response, error), keep func(T) bool) ([]T, string, error) {
+	pos, limit, err := in.start()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var out []T
+	reported := 0
+	for {
+		items, resp, err := fetch(github.ListOptions{Page: pos.Page, PerPage: pos.PerPage})
+		if err != nil {
+			return nil, "", err
+		}
+
+		next := 0
+		if resp != nil {
+			next = resp.NextPage
+		}
+
+		for i := pos.Offset; i < len(items); i++ {
+			if keep != nil && !keep(items[i]) {
+				continue
+			}
+			out = append(out, items[i])
+			if len(out) < limit {
+				continue
+			}
+			switch {
+			case i+1 < len(items):
+				return out, pageCursor{Page: pos.Page, PerPage: pos.PerPage, Offset: i + 1}.encode(), nil
+			case next != 0:
+				return out, pageCursor{Page: next, PerPage: pos.PerPage}.encode(), nil
+			default:
+				return out, "", nil
+			}
+		}
+
+		if next == 0 {
+			return out, "", nil
+		}
+		if len(out) > reported {
+			reported = len(out)
+			reportProgress(ctx, reported, limit, "fetched %d/%d %s", reported, limit, noun)
+		}
+		pos = pageCursor{Page: next, PerPage: pos.PerPage}
+	}
+}
+
+// pageDefaults applies the configured page size when the caller set none
+func (s *Service) pageDefaults(in PageInput) PageInput {
+	if in.PerPage == 0 {
+		in.PerPage = s.config.PerPage
+	}
+	return in
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePages serves the numbers 1..total in pages, like a GitHub listing endpoint
+func fakePages(total int, calls *int) func(opts github.ListOptions) ([]int, *github.Response, error) {
+	return func(opts github.ListOptions) ([]int, *github.Response, error) {
+		*calls++
+		var items []int
+		for n := (opts.Page-1)*opts.PerPage + 1; n <= total && len(items) < opts.PerPage; n++ {
+			items = append(items, n)
+		}
+		resp := &github.Response{}
+		if opts.Page*opts.PerPage < total {
+			resp.NextPage = opts.Page + 1
+		}
+		return items, resp, nil
+	}
+}
+
+func TestPaginateSinglePageByDefault(t *testing.T) {
+	var calls int
+	items, next, err := paginate(context.Background(), PageInput{PerPage: 10}, "items", fakePages(25, &calls), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, items)
+	assert.NotEmpty(t, next)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPaginateWalksAllPagesWithCursor(t *testing.T) {
+	var calls int
+	var all []int
+	in := PageInput{PerPage: 4, MaxResults: 6}
+	for {
+		items, next, err := paginate(context.Background(), in, "items", fakePages(15, &calls), nil)
+		require.NoError(t, err)
+		all = append(all, items...)
+		if next == "" {
+			break
+		}
+		in = PageInput{Cursor: next, MaxResults: 6}
+	}
+
+	var want []int
+	for n := 1; n <= 15; n++ {
+		want = append(want, n)
+	}
+	assert.Equal(t, want, all)
+}
+
+func TestPaginateFiltersAndResumesMidPage(t *testing.T) {
+	var calls int
+	even := func(n int) bool { return n%2 == 0 }
+
+	items, next, err := paginate(context.Background(), PageInput{PerPage: 10, MaxResults: 3}, "items", fakePages(20, &calls), even)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 6}, items)
+
+	items, next, err = paginate(context.Background(), PageInput{Cursor: next, MaxResults: 100}, "items", fakePages(20, &calls), even)
+	require.NoError(t, err)
+	assert.Equal(t, []int{8, 10, 12, 14, 16, 18, 20}, items)
+	assert.Empty(t, next)
+}
+
+func TestPaginateRejectsBadInput(t *testing.T) {
+	var calls int
+	for _, in := range []PageInput{
+		{Cursor: "not-a-cursor"},
+		{PerPage: 101},
+		{MaxResults: -1},
+	} {
+		_, _, err := paginate(context.Background(), in, "items", fakePages(5, &calls), nil)
+		assert.Error(t, err)
+	}
+	assert.Zero(t, calls)
+}
+
+func TestFakeGetOpenIssuesConfiguredPerPage(t *testing.T) {
+	svc, _ := newFakeService(t)
+	svc.config.PerPage = 2
+
+	issues, next, err := svc.GetOpenIssues(context.Background(), rawInput(t, ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, issueNumbers(issues))
+	assert.NotEmpty(t, next)
+}
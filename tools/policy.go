@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Operations a policy distinguishes between
+const (
+	operationRead  = "read"
+	operationWrite = "write"
+)
+
+// Policy restricts which repositories tools may read from and write to.
+// Writes must pass both the read and the write rules.
+type Policy struct {
+	Read  PolicyRules
+	Write PolicyRules
+}
+
+// PolicyRules are glob allow and deny lists. A pattern containing a slash
+// matches "owner/repo" (e.g. "acme/*", "acme/api-*"); one without matches
+// the owner alone (e.g. "acme", "team-*"). Matching is case-insensitive.
+// Deny rules win over allow rules, and an empty allowlist allows everything.
+type PolicyRules struct {
+	Allow []string
+	Deny  []string
+}
+
+// PolicyError reports that a policy rule blocked a tool call
+type PolicyError struct {
+	Operation string
+	Owner     string
+	Repo      string
+	Reason    string
+}
+
+func (e *PolicyError) Error() string {
+	target := e.Owner
+	if e.Repo != "" {
+		target += "/" + e.Repo
+	}
+	return fmt.Sprintf("policy denies %s access to %s: %s", e.Operation, target, e.Reason)
+}
+
+// Validate reports malformed glob patterns
+func (p Policy) Validate() error {
+	for _, rules := range []PolicyRules{p.Read, p.Write} {
+		for _, pattern := range append(append([]string{}, rules.Allow...), rules.Deny...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid policy pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns a *PolicyError when operation on owner/repo is not permitted.
+// An empty repo stands for every repository of owner, as in an org: search.
+func (p Policy) Check(operation, owner, repo string) error {
+	if err := p.Read.check(operationRead, owner, repo); err != nil {
+		return err
+	}
+	if operation == operationWrite {
+		return p.Write.check(operationWrite, owner, repo)
+	}
+	return nil
+}
+
+func (r PolicyRules) check(operation, owner, repo string) error {
+	deny := func(reason string) error {
+		return &PolicyError{Operation: operation, Owner: owner, Repo: repo, Reason: reason}
+	}
+	for _, pattern := range r.Deny {
+		if matchPolicy(pattern, owner, repo) {
+			return deny(fmt.Sprintf("matched %s deny rule %q", operation, pattern))
+		}
+	}
+	if len(r.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range r.Allow {
+		if matchPolicy(pattern, owner, repo) {
+			return nil
+		}
+	}
+	return deny(fmt.Sprintf("not matched by any %s allow rule (%s)", operation, strings.Join(r.Allow, ", ")))
+}
+
+// matchPolicy matches pattern against owner/repo, or against the owner alone
+// for patterns without a slash. With an empty repo only patterns covering
+// every repository of the owner match.
+func matchPolicy(pattern, owner, repo string) bool {
+	pattern = strings.ToLower(pattern)
+	owner, repo = strings.ToLower(owner), strings.ToLower(repo)
+
+	patternOwner, patternRepo, hasRepo := strings.Cut(pattern, "/")
+	if ok, _ := path.Match(patternOwner, owner); !ok {
+		return false
+	}
+	if !hasRepo {
+		return true
+	}
+	if repo == "" {
+		return patternRepo == "*"
+	}
+	ok, _ := path.Match(patternRepo, repo)
+	return ok
+}
+
+// authorize checks the policy for a tool call on owner/repo
+func (s *Service) authorize(operation, owner, repo string) error {
+	return s.config.Policy.Check(operation, owner, repo)
+}
+
+// authorizeQuery checks the policy for every repo:, org: and user: qualifier
+// in a search query, so extra qualifiers cannot widen a search past the policy
+func (s *Service) authorizeQuery(query string) error {
+	for _, field := range strings.Fields(query) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimPrefix(key, "-")) {
+		case "repo":
+			owner, repo, _ := strings.Cut(value, "/")
+			if err := s.authorize(operationRead, owner, repo); err != nil {
+				return err
+			}
+		case "org", "user":
+			if err := s.authorize(operationRead, value, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	policy := Policy{
+		Read: PolicyRules{
+			Allow: []string{"acme", "octo/public-*"},
+			Deny:  []string{"acme/secret*"},
+		},
+		Write: PolicyRules{
+			Allow: []string{"acme/widgets"},
+		},
+	}
+
+	tests := []struct {
+		operation, owner, repo string
+		allowed                bool
+	}{
+		{operationRead, "acme", "widgets", true},
+		{operationRead, "ACME", "Gadgets", true},
+		{operationRead, "acme", "secret-sauce", false},
+		{operationRead, "octo", "public-site", true},
+		{operationRead, "octo", "private", false},
+		{operationRead, "other", "repo", false},
+		{operationRead, "acme", "", true},
+		{operationRead, "octo", "", false},
+		{operationWrite, "acme", "widgets", true},
+		{operationWrite, "acme", "gadgets", false},
+		{operationWrite, "acme", "secret-widgets", false},
+	}
+	for _, tt := range tests {
+		err := policy.Check(tt.operation, tt.owner, tt.repo)
+		if tt.allowed {
+			assert.NoError(t, err, "%s %s/%s", tt.operation, tt.owner, tt.repo)
+		} else {
+			assert.Error(t, err, "%s %s/%s", tt.operation, tt.owner, tt.repo)
+		}
+	}
+}
+
+func TestPolicyErrorNamesRule(t *testing.T) {
+	policy := Policy{Read: PolicyRules{Deny: []string{"acme/secret*"}}, Write: PolicyRules{Allow: []string{"acme/widgets"}}}
+
+	err := policy.Check(operationRead, "acme", "secret-sauce")
+	var policyErr *PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, `policy denies read access to acme/secret-sauce: matched read deny rule "acme/secret*"`, err.Error())
+
+	err = policy.Check(operationWrite, "acme", "gadgets")
+	assert.EqualError(t, err, "policy denies write access to acme/gadgets: not matched by any write allow rule (acme/widgets)")
+}
+
+func TestPolicyValidate(t *testing.T) {
+	assert.NoError(t, Policy{Read: PolicyRules{Allow: []string{"acme/*"}}}.Validate())
+	assert.Error(t, Policy{Write: PolicyRules{Deny: []string{"acme/[widgets"}}}.Validate())
+
+	_, err := NewService(Config{Policy: Policy{Read: PolicyRules{Allow: []string{"["}}}})
+	assert.Error(t, err)
+}
+
+func TestPolicyBlocksToolsBeforeCallingGitHub(t *testing.T) {
+	svc, srv := newFakeService(t)
+	svc.config.Policy = Policy{Write: PolicyRules{Deny: []string{"acme"}}}
+	ctx := context.Background()
+
+	_, err := svc.CreateIssue(ctx, rawInput(t, CreateIssueInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Title: "Nope"}))
+	var policyErr *PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Empty(t, srv.Requests)
+
+	_, _, err = svc.GetOpenIssues(ctx, rawInput(t, ListIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	assert.NoError(t, err)
+}
+
+func TestPolicyChecksSearchQualifiers(t *testing.T) {
+	svc, _ := newFakeService(t)
+	svc.config.Policy = Policy{Read: PolicyRules{Allow: []string{"acme/widgets"}}}
+	ctx := context.Background()
+
+	_, _, err := svc.SearchIssues(ctx, rawInput(t, SearchIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Query: "crash"}))
+	assert.NoError(t, err)
+
+	for _, query := range []string{"crash repo:acme/secret", "crash org:acme", "crash user:octo"} {
+		_, _, err = svc.SearchIssues(ctx, rawInput(t, SearchIssuesInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}, Query: query}))
+		assert.Error(t, err, query)
+	}
+}
@@ -0,0 +1,46 @@
+package tools
+
+import "fmt"
+
+// PriorityConfig sets how many issues analyze_issue_priority looks at and the
+// scores at which an issue becomes critical, high or medium priority. Zero
+// values use the defaults: 20 issues and thresholds of 20, 10 and 5. A zero
+// threshold therefore cannot be set; thresholds start at 1.
+// search_issues with prioritize does not use it: it buckets comments plus
+// reactions at fixed thresholds.
+type PriorityConfig struct {
+	Limit    int `yaml:"limit"`
+	Critical int `yaml:"critical"`
+	High     int `yaml:"high"`
+	Medium   int `yaml:"medium"`
+}
+
+func (c PriorityConfig) withDefaults() PriorityConfig {
+	if c.Limit == 0 {
+		c.Limit = 20
+	}
+	if c.Critical == 0 {
+		c.Critical = 20
+	}
+	if c.High == 0 {
+		c.High = 10
+	}
+	if c.Medium == 0 {
+		c.Medium = 5
+	}
+	return c
+}
+
+// Validate reports a limit outside 1-100, a threshold below 1, or thresholds
+// that are not descending
+func (c PriorityConfig) Validate() error {
+	c = c.withDefaults()
+	if c.Limit < 1 || c.Limit > maxPerPage {
+		return fmt.Errorf("priority limit must be between 1 and %d", maxPerPage)
+	}
+	if c.Medium < 1 || c.High < c.Medium || c.Critical < c.High {
+		return fmt.Errorf("priority thresholds must satisfy critical >= high >= medium >= 1, got %d, %d and %d",
+			c.Critical, c.High, c.Medium)
+	}
+	return nil
+}
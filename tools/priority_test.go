@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityConfigValidate(t *testing.T) {
+	assert.NoError(t, PriorityConfig{}.Validate())
+	assert.NoError(t, PriorityConfig{Limit: 50, Critical: 30, High: 30, Medium: 0}.Validate())
+	assert.Error(t, PriorityConfig{Limit: 101}.Validate())
+	assert.Error(t, PriorityConfig{Critical: 8}.Validate(), "critical below the default high threshold")
+	assert.Error(t, PriorityConfig{Medium: -1}.Validate())
+	assert.NoError(t, PriorityConfig{Medium: 1}.Validate(), "1 is the lowest threshold that can be set")
+}
+
+func TestFakeAnalyzeIssuePriorityThresholds(t *testing.T) {
+	svc, _ := newFakeService(t)
+	svc.config.Priority = PriorityConfig{Limit: 20, Critical: 30, High: 15, Medium: 1}
+
+	analysis, err := svc.AnalyzeIssuePriority(context.Background(), rawInput(t, PriorityInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}}))
+	require.NoError(t, err)
+
+	// #1 scores 26: high rather than critical under the raised thresholds.
+	// #4 stays critical through its p0 label.
+	categories := map[int]string{}
+	for _, scored := range analysis {
+		categories[scored.Issue.GetNumber()] = scored.Category
+	}
+	assert.Equal(t, PriorityHigh, categories[1])
+	assert.Equal(t, PriorityCritical, categories[4])
+}
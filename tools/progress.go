@@ -0,0 +1,27 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProgressFunc is told how far a long-running call has got: done steps out
+// of total, where total is 0 when it is not known in advance, with a short
+// message such as "checked 40/120 PRs". done increases with every call.
+type ProgressFunc func(done, total int, message string)
+
+// progressKey is the context key holding the caller's ProgressFunc
+type progressKey struct{}
+
+// WithProgress returns a copy of ctx whose calls that page through results
+// or fan out over many requests report their progress to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress tells the ProgressFunc on ctx, if any, that done of total steps are complete
+func reportProgress(ctx context.Context, done, total int, format string, args ...interface{}) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(done, total, fmt.Sprintf(format, args...))
+	}
+}
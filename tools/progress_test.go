@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v56/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressEvent is one call of a ProgressFunc
+type progressEvent struct {
+	done, total int
+	message     string
+}
+
+func recordProgress(events *[]progressEvent) context.Context {
+	return WithProgress(context.Background(), func(done, total int, message string) {
+		*events = append(*events, progressEvent{done, total, message})
+	})
+}
+
+func TestPendingReviewsReportsProgress(t *testing.T) {
+	svc, srv := newFakeService(t)
+	repo := srv.Repo("acme", "widgets")
+	repo.PullRequests = nil
+	for n := 1; n <= 120; n++ {
+		repo.PullRequests = append(repo.PullRequests, &github.PullRequest{
+			Number: github.Int(n), Title: github.String(fmt.Sprintf("PR %d", n)), State: github.String("open"),
+		})
+	}
+
+	var events []progressEvent
+	prs, err := svc.GetPendingReviews(recordProgress(&events), rawInput(t, map[string]string{"owner": "acme", "repo": "widgets"}))
+	require.NoError(t, err)
+	assert.Len(t, prs, 99, "only the first 100 open PRs are checked; #3 is approved")
+
+	require.Len(t, events, 101)
+	assert.Equal(t, progressEvent{0, 100, "fetched 100 open PRs, checking their reviews"}, events[0])
+	assert.Equal(t, progressEvent{1, 100, "checked 1/100 PRs"}, events[1])
+	assert.Equal(t, progressEvent{40, 100, "checked 40/100 PRs"}, events[40])
+	assert.Equal(t, progressEvent{100, 100, "checked 100/100 PRs"}, events[100])
+}
+
+func TestPaginateReportsProgress(t *testing.T) {
+	var events []progressEvent
+	calls := 0
+	items, _, err := paginate(recordProgress(&events), PageInput{PerPage: 10, MaxResults: 25}, "items", fakePages(40, &calls), nil)
+	require.NoError(t, err)
+	assert.Len(t, items, 25)
+	assert.Equal(t, []progressEvent{{10, 25, "fetched 10/25 items"}, {20, 25, "fetched 20/25 items"}}, events)
+
+	// A single page needs no progress, and pages that keep nothing report nothing new
+	events = nil
+	_, _, err = paginate(recordProgress(&events), PageInput{PerPage: 10}, "items", fakePages(40, &calls), nil)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	none := func(int) bool { return false }
+	_, _, err = paginate(recordProgress(&events), PageInput{PerPage: 10}, "items", fakePages(40, &calls), none)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestAnalyzePriorityReportsProgress(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	var events []progressEvent
+	_, err := svc.AnalyzeIssuePriority(recordProgress(&events), rawInput(t, map[string]string{"owner": "acme", "repo": "widgets"}))
+	require.NoError(t, err)
+	assert.Equal(t, []progressEvent{
+		{1, 2, "fetched 3 open issues, scoring them"},
+		{2, 2, "scored 3 issues"},
+	}, events)
+}
+
+func TestNoProgressWithoutReceiver(t *testing.T) {
+	svc, _ := newFakeService(t)
+	_, err := svc.GetPendingReviews(context.Background(), rawInput(t, map[string]string{"owner": "acme", "repo": "widgets"}))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultMaxRetryWait = time.Minute
+	retryBaseDelay      = time.Second
+	retryMaxDelay       = 30 * time.Second
+	// secondaryLimitWait is GitHub's advice for secondary rate limits sent without Retry-After
+	secondaryLimitWait = time.Minute
+)
+
+// RetryPolicy controls how rate limited and failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is how often an idempotent request is retried. Zero uses the
+	// default of 3; a negative value disables retries.
+	MaxRetries int
+	// MaxWait is the longest the server waits for a rate limit to reset
+	// before giving up. Zero uses the default of one minute.
+	MaxWait time.Duration
+}
+
+// RateLimitedError reports that GitHub rate limited a call and waiting for
+// the limit to reset was abandoned
+type RateLimitedError struct {
+	// Until is when the limit resets, or zero when GitHub did not say
+	Until time.Time
+	Err   error
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.Until.IsZero() {
+		return "GitHub API rate limited; try again in a few minutes"
+	}
+	return fmt.Sprintf("GitHub API rate limited until %s; try again after that", e.Until.UTC().Format("15:04 MST"))
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// rateLimited converts go-github rate limit errors into a RateLimitedError
+// telling the caller when to try again; other errors are returned unchanged
+func (s *Service) rateLimited(err error) error {
+	var primary *github.RateLimitError
+	if errors.As(err, &primary) {
+		return &RateLimitedError{Until: primary.Rate.Reset.Time, Err: err}
+	}
+	var secondary *github.AbuseRateLimitError
+	if errors.As(err, &secondary) {
+		limited := &RateLimitedError{Err: err}
+		if secondary.RetryAfter != nil {
+			limited.Until = s.now().Add(*secondary.RetryAfter)
+		}
+		return limited
+	}
+	return err
+}
+
+// rateLimitTransport retries idempotent requests that hit GitHub's primary or
+// secondary rate limits or fail transiently. It honours Retry-After and
+// X-RateLimit-Reset, and otherwise backs off exponentially with full jitter.
+// When a wait would exceed the policy's MaxWait the rate limited response is
+// returned unchanged so go-github reports it.
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(max time.Duration) time.Duration
+}
+
+func newRateLimitTransport(base http.RoundTripper, policy RetryPolicy, now func() time.Time) *rateLimitTransport {
+	if policy.MaxRetries == 0 {
+		policy.MaxRetries = defaultMaxRetries
+	}
+	if policy.MaxWait == 0 {
+		policy.MaxWait = defaultMaxRetryWait
+	}
+	return &rateLimitTransport{
+		base:   base,
+		policy: policy,
+		now:    now,
+		sleep:  sleepContext,
+		jitter: func(max time.Duration) time.Duration { return time.Duration(rand.Int63n(int64(max) + 1)) },
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if !idempotent || attempt >= t.policy.MaxRetries {
+			return resp, err
+		}
+
+		wait, retry := t.retryAfter(resp, err, attempt)
+		if !retry || wait > t.policy.MaxWait {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryAfter decides whether a response is worth retrying and how long to wait first
+func (t *rateLimitTransport) retryAfter(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return t.backoff(attempt), true
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+				wait := time.Unix(reset, 0).Sub(t.now())
+				if wait < 0 {
+					wait = 0
+				}
+				return wait, true
+			}
+			return secondaryLimitWait, true
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return secondaryLimitWait, true
+		}
+		// A plain 403 is a permission problem, not a rate limit
+		return 0, false
+	case resp.StatusCode >= 500:
+		return t.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff returns a jittered exponential delay for the given attempt
+func (t *rateLimitTransport) backoff(attempt int) time.Duration {
+	d := retryBaseDelay << attempt
+	if d > retryMaxDelay || d <= 0 {
+		d = retryMaxDelay
+	}
+	return t.jitter(d)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRateLimitTransport returns a transport that records waits instead of sleeping
+func newTestRateLimitTransport(now time.Time, waits *[]time.Duration) *rateLimitTransport {
+	rt := newRateLimitTransport(http.DefaultTransport, RetryPolicy{}, func() time.Time { return now })
+	rt.sleep = func(_ context.Context, d time.Duration) error {
+		*waits = append(*waits, d)
+		return nil
+	}
+	rt.jitter = func(max time.Duration) time.Duration { return max }
+	return rt
+}
+
+func TestRateLimitTransportRetriesServerErrors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRateLimitTransport(time.Now(), &waits)}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+}
+
+func TestRateLimitTransportHonoursRetryAfter(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRateLimitTransport(time.Now(), &waits)}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{7 * time.Second}, waits)
+}
+
+func TestRateLimitTransportDoesNotRetryWrites(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var waits []time.Duration
+	client := &http.Client{Transport: newTestRateLimitTransport(time.Now(), &waits)}
+	resp, err := client.Post(srv.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, waits)
+}
+
+func TestRateLimitedErrorReportsReset(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	reset := time.Date(2025, 1, 1, 12, 34, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset.Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+	}))
+	defer srv.Close()
+
+	// The reset is further away than MaxWait, so the call gives up immediately
+	svc, err := NewService(Config{BaseURL: srv.URL, Now: func() time.Time { return now }})
+	require.NoError(t, err)
+
+	raw, _ := json.Marshal(ListPullRequestsInput{RepoInput: RepoInput{Owner: "acme", Repo: "widgets"}})
+	_, _, err = svc.GetOpenPRs(context.Background(), raw)
+	require.Error(t, err)
+
+	var limited *RateLimitedError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, "GitHub API rate limited until 12:34 UTC; try again after that", err.Error())
+}
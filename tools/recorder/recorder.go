@@ -0,0 +1,216 @@
+// Package recorder records GitHub API exchanges into cassette files with
+// credentials scrubbed, and replays them offline.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted replaces credentials in recorded cassettes
+const redacted = "[REDACTED]"
+
+// githubTokenPattern matches GitHub personal access, OAuth, app and refresh tokens
+var githubTokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+// Mode selects whether a Recorder talks to GitHub or serves a cassette
+type Mode int
+
+const (
+	// ModeReplay serves recorded responses and never touches the network
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests and appends each exchange to the cassette
+	ModeRecord
+)
+
+// Interaction is one recorded HTTP exchange
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request used to match it on replay
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as served back on replay
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records GitHub exchanges into a
+// cassette file, or replays them offline. Credentials are scrubbed before
+// anything is written.
+type Recorder struct {
+	mode Mode
+	path string
+	real http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder opens the cassette at path. In ModeRecord requests go through
+// real (http.DefaultTransport when nil) and the cassette is rewritten after
+// every exchange; in ModeReplay the cassette must already exist.
+func NewRecorder(path string, mode Mode, real http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{mode: mode, path: path, real: real}
+	if r.real == nil {
+		r.real = http.DefaultTransport
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.interactions))
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body string) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != req.URL.String() || in.Request.Body != body {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        http.StatusText(in.Response.StatusCode),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette %s has no recorded interaction for %s %s", r.path, req.Method, req.URL)
+}
+
+func (r *Recorder) record(req *http.Request, body string) (*http.Response, error) {
+	resp, err := r.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	secret := credential(req.Header.Get("Authorization"))
+	header := req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: header,
+			Body:   scrub(body, secret),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrubHeader(resp.Header, secret),
+			Body:       scrub(string(respBody), secret),
+		},
+	})
+	return resp, r.save()
+}
+
+// save rewrites the cassette with every interaction recorded so far
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// readBody returns the request body as a string, leaving it readable for the real transport
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", errors.New("failed to read request body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return string(data), nil
+}
+
+// credential extracts the secret from an Authorization header value
+func credential(header string) string {
+	if _, secret, ok := strings.Cut(header, " "); ok {
+		return strings.TrimSpace(secret)
+	}
+	return header
+}
+
+// scrub removes the request's credential and anything shaped like a GitHub token
+func scrub(s, secret string) string {
+	if secret != "" {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return githubTokenPattern.ReplaceAllString(s, redacted)
+}
+
+func scrubHeader(h http.Header, secret string) http.Header {
+	out := make(http.Header, len(h))
+	for k, values := range h {
+		if k == "Set-Cookie" {
+			continue
+		}
+		for _, v := range values {
+			out.Add(k, scrub(v, secret))
+		}
+	}
+	return out
+}
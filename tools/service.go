@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Config holds the settings a Service is built from
+type Config struct {
+	// Token is the server identity for github.com, normally GITHUB_TOKEN
+	Token string
+	// BaseURL overrides the github.com API root, e.g. to point at a fake in tests
+	BaseURL string
+	// Enterprise routes some or all owners to a GitHub Enterprise Server host
+	Enterprise EnterpriseConfig
+	// App authenticates as a GitHub App installation instead of Token
+	App *AppAuth
+	// Transport is the shared HTTP transport. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Retry controls retries of rate limited and failed requests
+	Retry RetryPolicy
+	// Cache bounds the ETag response cache
+	Cache CacheConfig
+	// CompletionTTL is how long the names behind argument completions are
+	// reused before GitHub is asked again. Zero uses the default of one
+	// minute; a negative value disables caching.
+	CompletionTTL time.Duration
+	// Policy limits which repositories tools may read from and write to
+	Policy Policy
+	// PerPage is the page size listings use when the caller sets none. Defaults to 100.
+	PerPage int
+	// Priority sets how analyze_issue_priority scores and buckets issues
+	Priority PriorityConfig
+	// Now is the clock used for priority scoring. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Validate reports settings NewService cannot use
+func (c Config) Validate() error {
+	if c.PerPage < 0 || c.PerPage > maxPerPage {
+		return fmt.Errorf("per_page must be between 1 and %d", maxPerPage)
+	}
+	if c.Enterprise.Enabled() {
+		if _, err := url.Parse(c.Enterprise.BaseURL); err != nil {
+			return fmt.Errorf("invalid enterprise base_url: %w", err)
+		}
+		if c.Token != "" && c.Enterprise.Token == "" && c.App == nil && !c.Enterprise.ShareToken {
+			return errors.New("an enterprise host is configured without its own token: set the enterprise token, " +
+				"or share_token to send the github.com token to it")
+		}
+	}
+	if err := c.Priority.Validate(); err != nil {
+		return err
+	}
+	return c.Policy.Validate()
+}
+
+// enterpriseToken is the server token sent to the enterprise host
+func (c Config) enterpriseToken() string {
+	if c.Enterprise.Token == "" && c.Enterprise.ShareToken {
+		return c.Token
+	}
+	return c.Enterprise.Token
+}
+
+// Service is the long-lived GitHub client shared by every tool. It is built
+// once at startup so all calls reuse the same pooled connections.
+type Service struct {
+	github     *github.Client
+	enterprise *github.Client
+	transport  http.RoundTripper
+	config     Config
+	now        func() time.Time
+	// logins remembers the GitHub login behind each token, see Identity
+	logins sync.Map
+	// completions caches the names argument completions are drawn from
+	completions completionCache
+}
+
+// NewService creates a Service with one client per configured host
+func NewService(cfg Config) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.PerPage == 0 {
+		cfg.PerPage = maxPerPage
+	}
+	cfg.Priority = cfg.Priority.withDefaults()
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.CompletionTTL == 0 {
+		cfg.CompletionTTL = defaultCompletionTTL
+	}
+
+	s := &Service{
+		transport: newCacheTransport(newRateLimitTransport(cfg.Transport, cfg.Retry, cfg.Now), cfg.Cache, cfg.Now),
+		config:    cfg,
+		now:       cfg.Now,
+	}
+
+	var err error
+	s.github, err = s.newHostClient(cfg.Token, func(c *github.Client) (*github.Client, error) {
+		if cfg.BaseURL == "" {
+			return c, nil
+		}
+		base, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/") + "/")
+		if err != nil {
+			return nil, err
+		}
+		c.BaseURL, c.UploadURL = base, base
+		return c, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Enterprise.Enabled() {
+		s.enterprise, err = s.newHostClient(cfg.enterpriseToken(), func(c *github.Client) (*github.Client, error) {
+			return c.WithEnterpriseURLs(cfg.Enterprise.BaseURL, cfg.Enterprise.uploadURL())
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Now returns the current time by the service's clock
+func (s *Service) Now() time.Time {
+	return s.now()
+}
+
+// newHostClient builds a client for one host, authenticated per request by authTransport
+func (s *Service) newHostClient(token string, route func(*github.Client) (*github.Client, error)) (*github.Client, error) {
+	auth := &authTransport{base: s.transport, token: token}
+	if s.config.App != nil {
+		appClient, err := route(github.NewClient(&http.Client{
+			Transport: &appJWTTransport{app: s.config.App, base: s.transport},
+		}))
+		if err != nil {
+			return nil, err
+		}
+		auth.app = s.config.App
+		auth.appClient = appClient
+	}
+	return route(github.NewClient(&http.Client{Transport: auth}))
+}
+
+// clientFor returns the client for the host serving owner, along with a
+// context that identifies owner to the auth transport
+func (s *Service) clientFor(ctx context.Context, owner string) (context.Context, *github.Client) {
+	ctx = withOwner(ctx, owner)
+	if s.enterprise != nil && s.config.Enterprise.Routes(owner) {
+		return ctx, s.enterprise
+	}
+	return ctx, s.github
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// IssueThread is an issue with its comments, oldest first
+type IssueThread struct {
+	Issue    *github.Issue
+	Comments []*github.IssueComment
+}
+
+// PullRequestThread is a pull request with its reviews and conversation comments, oldest first
+type PullRequestThread struct {
+	PullRequest *github.PullRequest
+	Reviews     []*github.PullRequestReview
+	Comments    []*github.IssueComment
+}
+
+// GetIssueThread returns issue number in owner/repo with all of its comments
+func (s *Service) GetIssueThread(ctx context.Context, owner, repo string, number int) (*IssueThread, error) {
+	if err := validateThread(owner, repo, number); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(operationRead, owner, repo); err != nil {
+		return nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, owner)
+	issue, _, err := client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+	comments, err := s.listComments(ctx, client, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return &IssueThread{Issue: issue, Comments: comments}, nil
+}
+
+// GetPullRequestThread returns pull request number in owner/repo with all of its reviews and comments
+func (s *Service) GetPullRequestThread(ctx context.Context, owner, repo string, number int) (*PullRequestThread, error) {
+	if err := validateThread(owner, repo, number); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(operationRead, owner, repo); err != nil {
+		return nil, err
+	}
+
+	ctx, client := s.clientFor(ctx, owner)
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, s.rateLimited(err)
+	}
+
+	thread := &PullRequestThread{PullRequest: pr}
+	opts := &github.ListOptions{PerPage: maxPerPage}
+	for {
+		reviews, resp, err := client.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, s.rateLimited(err)
+		}
+		thread.Reviews = append(thread.Reviews, reviews...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// The conversation of a pull request lives on its issue
+	if thread.Comments, err = s.listComments(ctx, client, owner, repo, number); err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// listComments returns every comment on issue or pull request number
+func (s *Service) listComments(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.IssueComment, error) {
+	var all []*github.IssueComment
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: maxPerPage}}
+	for {
+		comments, resp, err := client.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, s.rateLimited(err)
+		}
+		all = append(all, comments...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func validateThread(owner, repo string, number int) error {
+	if owner == "" || repo == "" {
+		return invalidInput("owner and repo are required")
+	}
+	if number < 1 {
+		return invalidInput("number must be a positive integer, got %d", number)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeGetIssueThread(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	thread, err := svc.GetIssueThread(context.Background(), "acme", "widgets", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Crash on startup", thread.Issue.GetTitle())
+	require.Len(t, thread.Comments, 2)
+	assert.Equal(t, "alice", thread.Comments[0].GetUser().GetLogin())
+	assert.Equal(t, "Confirmed on 2.1", thread.Comments[1].GetBody())
+
+	_, err = svc.GetIssueThread(context.Background(), "acme", "widgets", 99)
+	assert.Error(t, err)
+}
+
+func TestFakeGetPullRequestThread(t *testing.T) {
+	svc, _ := newFakeService(t)
+
+	thread, err := svc.GetPullRequestThread(context.Background(), "acme", "widgets", 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Add feature", thread.PullRequest.GetTitle())
+	require.Len(t, thread.Reviews, 1)
+	assert.Equal(t, "APPROVED", thread.Reviews[0].GetState())
+	require.Len(t, thread.Comments, 1)
+	assert.Equal(t, "Looks good", thread.Comments[0].GetBody())
+}
+
+func TestGetThreadValidatesInput(t *testing.T) {
+	svc, srv := newFakeService(t)
+
+	_, err := svc.GetIssueThread(context.Background(), "acme", "widgets", 0)
+	var inputErr *InputError
+	require.ErrorAs(t, err, &inputErr)
+	_, err = svc.GetPullRequestThread(context.Background(), "", "widgets", 3)
+	require.ErrorAs(t, err, &inputErr)
+
+	svc.config.Policy = Policy{Read: PolicyRules{Deny: []string{"acme/widgets"}}}
+	_, err = svc.GetIssueThread(context.Background(), "acme", "widgets", 1)
+	var policyErr *PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Empty(t, srv.Requests)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Toolsets group the tools that can be enabled together
+const (
+	toolsetIssues    = "issues"
+	toolsetPulls     = "pulls"
+	toolsetSearch    = "search"
+	toolsetAnalytics = "analytics"
+	toolsetWrites    = "writes"
+	toolsetAudit     = "audit"
+	// toolsetContents holds the repository content resources rather than tools
+	toolsetContents = "contents"
+)
+
+// allToolsets lists every toolset, in the order tools are registered
+var allToolsets = []string{toolsetIssues, toolsetPulls, toolsetSearch, toolsetAnalytics, toolsetWrites, toolsetAudit, toolsetContents}
+
+// serverOptions selects which tools the server exposes and how it introduces itself
+type serverOptions struct {
+	// Name and Version are reported to clients. Default to "GitHub MCP Server" and "0.1.0".
+	Name    string
+	Version string
+	// ReadOnly disables the writes toolset and makes mutating handlers refuse to run
+	ReadOnly bool
+	// DryRun makes mutating tools preview their request unless a call sets dry_run=false
+	DryRun bool
+	// Toolsets enabled on the server. Empty enables all of them.
+	Toolsets []string
+	// Audit records every tool call when set, and backs the audit toolset
+	Audit *auditLog
+	// MultiTenant is set when the server is shared by callers with their own
+	// tokens. The audit toolset is then left out, since the log holds every
+	// caller's calls.
+	MultiTenant bool
+}
+
+// toolsetTool is a tool with its handler and the toolset it belongs to
+type toolsetTool struct {
+	toolset string
+	tool    mcp.Tool
+	handler server.ToolHandlerFunc
+}
+
+// toolsetPrompt is a prompt with its handler and the toolsets whose tools it relies on
+type toolsetPrompt struct {
+	toolsets []string
+	prompt   mcp.Prompt
+	handler  server.PromptHandlerFunc
+}
+
+// validate reports toolset names that do not exist
+func (o serverOptions) validate() error {
+	for _, name := range o.Toolsets {
+		if !contains(allToolsets, name) {
+			return fmt.Errorf("unknown toolset %q (expected one of %s)", name, strings.Join(allToolsets, ", "))
+		}
+	}
+	return nil
+}
+
+// enabled reports whether tools in toolset should be registered
+func (o serverOptions) enabled(toolset string) bool {
+	if o.ReadOnly && toolset == toolsetWrites {
+		return false
+	}
+	if (o.Audit == nil || o.MultiTenant) && toolset == toolsetAudit {
+		return false
+	}
+	return len(o.Toolsets) == 0 || contains(o.Toolsets, toolset)
+}
+
+// isMutating reports whether tool can modify GitHub, going by its read-only hint
+func isMutating(tool mcp.Tool) bool {
+	hint := tool.Annotations.ReadOnlyHint
+	return hint == nil || !*hint
+}
+
+// parseToolsets splits a comma-separated toolset list; "all" or "" enables every toolset
+func parseToolsets(s string) []string {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "all" {
+			return nil
+		}
+		if name != "" {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
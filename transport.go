@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// Supported transports for serving the MCP server
+const (
+	transportStdio = "stdio"
+	transportSSE   = "sse"
+	transportHTTP  = "http"
+)
+
+// transportConfig selects how the MCP server is exposed to clients
+type transportConfig struct {
+	Transport string `yaml:"type"`
+	Addr      string `yaml:"addr"`
+	BasePath  string `yaml:"base_path"`
+
+	// ShareServerToken lets HTTP callers without an Authorization header act
+	// as the process-wide GITHUB_TOKEN. When false they are unauthenticated.
+	ShareServerToken bool `yaml:"share_server_token"`
+}
+
+// multiTenant reports whether the transport serves many callers, each with their own token
+func (c transportConfig) multiTenant() bool {
+	transport := strings.ToLower(c.Transport)
+	return transport != "" && transport != transportStdio
+}
+
+// httpServer is the common surface of the SSE and streamable HTTP servers
+type httpServer interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+// serve runs s on the configured transport until it stops or the process is interrupted
+func serve(s *server.MCPServer, cfg transportConfig) error {
+	if transport := strings.ToLower(cfg.Transport); transport == "" || transport == transportStdio {
+		return server.ServeStdio(s)
+	}
+	srv, err := newHTTPServer(s, cfg)
+	if err != nil {
+		return err
+	}
+	return serveHTTP(srv, cfg.Addr)
+}
+
+// newHTTPServer builds the SSE or streamable HTTP server for cfg. Both carry
+// the caller's Authorization token into every tool call.
+func newHTTPServer(s *server.MCPServer, cfg transportConfig) (httpServer, error) {
+	switch strings.ToLower(cfg.Transport) {
+	case transportSSE:
+		return server.NewSSEServer(s,
+			server.WithBasePath(cfg.BasePath),
+			server.WithSSEContextFunc(callerTokenContext(cfg.ShareServerToken)),
+		), nil
+	case transportHTTP, "streamable-http":
+		return server.NewStreamableHTTPServer(s,
+			server.WithEndpointPath(cfg.BasePath),
+			server.WithHTTPContextFunc(callerTokenContext(cfg.ShareServerToken)),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q (expected stdio, sse or http)", cfg.Transport)
+	}
+}
+
+// serveHTTP starts an HTTP based transport and shuts it down gracefully on SIGINT/SIGTERM
+func serveHTTP(srv httpServer, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "GitHub MCP Server listening on %s\n", addr)
+		errCh <- srv.Start(addr)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// callerTokenContext carries the token from each request's Authorization header
+// into the tool call context, so every call runs with the caller's own permissions
+func callerTokenContext(shareServerToken bool) func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		token := bearerToken(r.Header.Get("Authorization"))
+		if token == "" && shareServerToken {
+			return ctx
+		}
+		return tools.WithToken(ctx, token)
+	}
+}
+
+// bearerToken extracts the credential from a "Bearer <token>" or "token <token>" header value
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok {
+		return ""
+	}
+	switch strings.ToLower(scheme) {
+	case "bearer", "token":
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
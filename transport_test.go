@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/himanshusharma89/github-mcp-server/tools"
+)
+
+// fakeHTTPServer is an httpServer whose Start blocks until Shutdown, or returns startErr
+type fakeHTTPServer struct {
+	startErr error
+	started  chan string
+	stopped  chan struct{}
+	shutdown bool
+}
+
+func newFakeHTTPServer(startErr error) *fakeHTTPServer {
+	return &fakeHTTPServer{startErr: startErr, started: make(chan string, 1), stopped: make(chan struct{})}
+}
+
+func (f *fakeHTTPServer) Start(addr string) error {
+	f.started <- addr
+	if f.startErr != nil {
+		return f.startErr
+	}
+	<-f.stopped
+	return http.ErrServerClosed
+}
+
+func (f *fakeHTTPServer) Shutdown(context.Context) error {
+	f.shutdown = true
+	close(f.stopped)
+	return nil
+}
+
+func TestServeUnknownTransport(t *testing.T) {
+	err := serve(server.NewMCPServer("test", "0"), transportConfig{Transport: "websocket"})
+	assert.EqualError(t, err, `unknown transport "websocket" (expected stdio, sse or http)`)
+}
+
+func TestServeHTTPStartErrors(t *testing.T) {
+	srv := newFakeHTTPServer(errors.New("address already in use"))
+	assert.EqualError(t, serveHTTP(srv, ":8080"), "address already in use")
+	assert.Equal(t, ":8080", <-srv.started)
+
+	// A server closed from elsewhere is a clean stop
+	assert.NoError(t, serveHTTP(newFakeHTTPServer(http.ErrServerClosed), ":8080"))
+}
+
+func TestServeHTTPShutsDownOnSignal(t *testing.T) {
+	srv := newFakeHTTPServer(nil)
+	done := make(chan error, 1)
+	go func() { done <- serveHTTP(srv, ":8080") }()
+
+	// Start runs once the signal handler is installed, so the signal is caught rather than fatal
+	<-srv.started
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+		assert.True(t, srv.shutdown)
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveHTTP did not shut down on SIGTERM")
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	for header, want := range map[string]string{
+		"Bearer ghp_abc":     "ghp_abc",
+		"bearer ghp_abc":     "ghp_abc",
+		"token ghp_abc":      "ghp_abc",
+		"Token  ghp_abc ":    "ghp_abc",
+		"Basic dXNlcjpwYXNz": "",
+		"Bearer":             "",
+		"ghp_abc":            "",
+		"":                   "",
+	} {
+		assert.Equal(t, want, bearerToken(header), "header %q", header)
+	}
+}
+
+func TestCallerTokenContext(t *testing.T) {
+	request := func(header string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return r
+	}
+	tokenOf := func(share bool, header string) (string, bool) {
+		return tools.TokenFromContext(callerTokenContext(share)(context.Background(), request(header)))
+	}
+
+	for _, share := range []bool{false, true} {
+		token, ok := tokenOf(share, "Bearer ghp_caller")
+		assert.True(t, ok)
+		assert.Equal(t, "ghp_caller", token, "a caller's own token always wins")
+	}
+
+	// Without a header the caller is anonymous, not the server identity...
+	token, ok := tokenOf(false, "")
+	assert.True(t, ok)
+	assert.Empty(t, token)
+	token, ok = tokenOf(false, "Basic dXNlcjpwYXNz")
+	assert.True(t, ok)
+	assert.Empty(t, token)
+
+	// ...unless the server token is shared, which leaves the context without a caller token
+	_, ok = tokenOf(true, "")
+	assert.False(t, ok)
+}
+
+// newTokenEchoServer returns an MCP server whose whoami tool reports the caller token it runs with
+func newTokenEchoServer() *server.MCPServer {
+	s := server.NewMCPServer("test", "0")
+	s.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		token, ok := tools.TokenFromContext(ctx)
+		return mcp.NewToolResultText(fmt.Sprintf("token=%q set=%v", token, ok)), nil
+	})
+	return s
+}
+
+func TestHTTPTransportsUseCallerToken(t *testing.T) {
+	for _, tc := range []struct {
+		transport string
+		connect   func(url string, headers map[string]string) (*client.Client, error)
+	}{
+		{transportSSE, func(url string, headers map[string]string) (*client.Client, error) {
+			return client.NewSSEMCPClient(url+"/mcp/sse", transport.WithHeaders(headers))
+		}},
+		{transportHTTP, func(url string, headers map[string]string) (*client.Client, error) {
+			return client.NewStreamableHttpClient(url+"/mcp", transport.WithHTTPHeaders(headers))
+		}},
+	} {
+		t.Run(tc.transport, func(t *testing.T) {
+			whoami := func(share bool, headers map[string]string) string {
+				srv, err := newHTTPServer(newTokenEchoServer(), transportConfig{Transport: tc.transport, BasePath: "/mcp", ShareServerToken: share})
+				require.NoError(t, err)
+				ts := httptest.NewServer(srv.(http.Handler))
+				defer ts.Close()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				c, err := tc.connect(ts.URL, headers)
+				require.NoError(t, err)
+				defer c.Close()
+				require.NoError(t, c.Start(ctx))
+				_, err = c.Initialize(ctx, mcp.InitializeRequest{})
+				require.NoError(t, err)
+
+				req := mcp.CallToolRequest{}
+				req.Params.Name = "whoami"
+				res, err := c.CallTool(ctx, req)
+				require.NoError(t, err)
+				require.Len(t, res.Content, 1)
+				return res.Content[0].(mcp.TextContent).Text
+			}
+
+			assert.Equal(t, `token="ghp_caller" set=true`, whoami(false, map[string]string{"Authorization": "Bearer ghp_caller"}))
+			assert.Equal(t, `token="ghp_caller" set=true`, whoami(true, map[string]string{"Authorization": "token ghp_caller"}))
+			assert.Equal(t, `token="" set=true`, whoami(false, nil))
+			assert.Equal(t, `token="" set=false`, whoami(true, nil))
+		})
+	}
+}